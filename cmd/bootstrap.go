@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+)
+
+// createModerator заводит первого модератора, дальше модераторы приходят по приглашениям.
+// Пароль можно передать через MODERATOR_PASSWORD, чтобы он не светился в списке процессов
+func createModerator(log *slog.Logger, cfg *config.Config, db repository.Database, args []string) error {
+	flags := flag.NewFlagSet("create-moderator", flag.ContinueOnError)
+	email := flags.String("email", "", "moderator email")
+	password := flags.String("password", os.Getenv("MODERATOR_PASSWORD"), "moderator password")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" || *password == "" {
+		return fmt.Errorf("email and password are required")
+	}
+
+	passwordPolicy, err := service.NewPasswordPolicy(cfg.Auth.Password, cfg.Auth.Hashing)
+	if err != nil {
+		return err
+	}
+
+	passwordHasher, err := service.NewPasswordHasher(cfg.Auth.Hashing)
+	if err != nil {
+		return err
+	}
+
+	userService := service.NewUserService(db, []byte(cfg.HTTP.JWTSecret), cfg.Auth, passwordPolicy, passwordHasher, nil)
+
+	user, err := userService.Bootstrap(*email, *password)
+	if err != nil {
+		return err
+	}
+
+	log.Info("moderator created", "id", user.ID, "email", user.Email)
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+// checkEmailDuplicates выводит учётные записи, которые станут конфликтовать
+// после перехода на регистронезависимую уникальность почты.
+// Их нужно объединить или удалить до применения миграции 006
+func checkEmailDuplicates(log *slog.Logger, db repository.Database) error {
+	duplicates, err := db.FindEmailDuplicates()
+	if err != nil {
+		return err
+	}
+
+	if len(duplicates) == 0 {
+		log.Info("no email duplicates found")
+		return nil
+	}
+
+	for _, duplicate := range duplicates {
+		log.Warn("email duplicate",
+			"normalized", duplicate.Normalized,
+			"emails", strings.Join(duplicate.Emails, ", "),
+			"user_ids", strings.Join(duplicate.UserIDs, ", "),
+		)
+	}
+
+	return fmt.Errorf("found %d duplicate email groups", len(duplicates))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// duplicatesDB отдаёт заранее заданный результат FindEmailDuplicates, остальные методы паникуют
+type duplicatesDB struct {
+	repository.Database
+
+	duplicates []model.EmailDuplicate
+	err        error
+}
+
+func (f *duplicatesDB) FindEmailDuplicates() ([]model.EmailDuplicate, error) {
+	return f.duplicates, f.err
+}
+
+func TestCheckEmailDuplicates_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name        string
+		db          *duplicatesDB
+		expectedErr string
+		expectedLog []string
+	}{
+		{
+			name:        "no_duplicates",
+			db:          &duplicatesDB{duplicates: []model.EmailDuplicate{}},
+			expectedLog: []string{"no email duplicates found"},
+		},
+		{
+			name: "duplicates_found",
+			db: &duplicatesDB{duplicates: []model.EmailDuplicate{
+				{
+					Normalized: "user@example.com",
+					UserIDs:    []string{"id-1", "id-2"},
+					Emails:     []string{"user@example.com", "User@Example.com"},
+				},
+				{
+					Normalized: "other@example.com",
+					UserIDs:    []string{"id-3", "id-4"},
+					Emails:     []string{"other@example.com", " other@example.com"},
+				},
+			}},
+			expectedErr: "found 2 duplicate email groups",
+			expectedLog: []string{
+				`normalized=user@example.com`,
+				`emails="user@example.com, User@Example.com"`,
+				`user_ids="id-1, id-2"`,
+				`normalized=other@example.com`,
+			},
+		},
+		{
+			name:        "db_error",
+			db:          &duplicatesDB{err: errors.New("connection refused")},
+			expectedErr: "connection refused",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewTextHandler(&buf, nil))
+
+			err := checkEmailDuplicates(log, tc.db)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			for _, line := range tc.expectedLog {
+				assert.Contains(t, buf.String(), line)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+)
+
+// startJobs запускает фоновые задачи. На нескольких репликах задачи запускаются на каждой,
+// от повторной работы защищают advisory lock в базе. Задачи останавливаются при отмене ctx,
+// jobs дожидается их завершения
+func startJobs(ctx context.Context, jobs *sync.WaitGroup, log *slog.Logger, cfg *config.Config, db repository.Database) error {
+	if cfg.Jobs.StaleReceptions.TTL == 0 {
+		log.Info("stale receptions job disabled")
+		return nil
+	}
+
+	stale, err := service.NewStaleReceptionService(db, cfg.Jobs.StaleReceptions)
+	if err != nil {
+		return err
+	}
+
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+
+		ticker := time.NewTicker(stale.Interval())
+		defer ticker.Stop()
+
+		// Первый проход сразу после старта, не дожидаясь интервала
+		for {
+			runStaleReceptions(log, stale)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func runStaleReceptions(log *slog.Logger, stale service.StaleReceptionService) {
+	receptions, locked, err := stale.ProcessStale()
+	if err != nil {
+		log.Error("stale receptions job failed", "error", err)
+		return
+	}
+
+	if !locked {
+		log.Debug("stale receptions job is running on another replica")
+		return
+	}
+
+	for _, reception := range receptions {
+		log.Warn("stale reception processed",
+			"reception_id", reception.ID,
+			"pvz_id", reception.PvzID,
+			"action", service.StaleAction(reception),
+			"opened_at", reception.DateTime,
+			"age", time.Since(reception.DateTime).Round(time.Minute).String(),
+		)
+	}
+
+	log.Info("stale receptions job finished", "action", stale.Action(), "processed", len(receptions))
+}
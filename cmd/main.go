@@ -1,8 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	// Часовые пояса ПВЗ не должны зависеть от tzdata в образе
+	_ "time/tzdata"
 
 	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
@@ -11,6 +20,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// shutdownTimeout - сколько ждать завершения текущих запросов при остановке
+const shutdownTimeout = 10 * time.Second
+
 type App struct {
 	Cfg    *config.Config
 	Logger *slog.Logger
@@ -40,9 +52,57 @@ func main() {
 	}
 	defer pg.Close()
 
+	// Служебные подкоманды, например: go run ./cmd create-moderator -email ... -password ...
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "create-moderator":
+			if err := createModerator(log, cfg, pg, os.Args[2:]); err != nil {
+				log.Error("failed moderator create", "error", err)
+			}
+			return
+		case "check-email-duplicates":
+			if err := checkEmailDuplicates(log, pg); err != nil {
+				log.Error("failed email duplicates check", "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Echo Handler
-	e := handler.New(log, pg, []byte(cfg.HTTP.JWTSecret))
-	if err := e.Start(":" + cfg.HTTP.Port); err != nil {
-		log.Error("failed server start ", "error", err)
+	e, err := handler.New(log, pg, cfg)
+	if err != nil {
+		log.Error("failed handler create", "error", err)
+		return
 	}
+
+	// SIGINT/SIGTERM останавливают сервер и фоновые задачи
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Фоновые задачи
+	var jobs sync.WaitGroup
+	if err := startJobs(ctx, &jobs, log, cfg, pg); err != nil {
+		log.Error("failed jobs start", "error", err)
+		return
+	}
+
+	go func() {
+		if err := e.Start(":" + cfg.HTTP.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed server start ", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed server shutdown", "error", err)
+	}
+
+	// База закрывается отложенным pg.Close, поэтому сначала дожидаемся задач
+	jobs.Wait()
 }
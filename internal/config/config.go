@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +12,31 @@ type Config struct {
 	HTTP HTTP     `yaml:"http_server"`
 	DB   Database `yaml:"database"`
 	GRPC GRPC     `yaml:"grpc"`
+	Auth Auth     `yaml:"auth"`
+	Jobs Jobs     `yaml:"jobs"`
+
+	Receptions Receptions `yaml:"receptions"`
+}
+
+// Receptions: ReopenWindow - сколько после закрытия приёмку ещё можно переоткрыть, 0 запрещает переоткрытие.
+// BarcodeDuplicateWindow - сколько после приёмки повторный скан того же штрихкода считается дублем,
+// при 0 дублем считается только товар в открытой приёмке
+type Receptions struct {
+	ReopenWindow           time.Duration `yaml:"reopen_window"`
+	BarcodeDuplicateWindow time.Duration `yaml:"barcode_duplicate_window"`
+}
+
+// Jobs - фоновые задачи, которые приложение запускает рядом с HTTP-сервером
+type Jobs struct {
+	StaleReceptions StaleReceptions `yaml:"stale_receptions"`
+}
+
+// StaleReceptions - поиск приёмок, открытых дольше TTL. TTL 0 отключает задачу.
+// Action: close - закрыть приёмку (пустая только помечается), flag - только пометить для разбора
+type StaleReceptions struct {
+	TTL      time.Duration `yaml:"ttl"`
+	Interval time.Duration `yaml:"interval"`
+	Action   string        `yaml:"action"`
 }
 
 type HTTP struct {
@@ -18,6 +44,62 @@ type HTTP struct {
 	JWTSecret string `yaml:"jwt_secret"`
 }
 
+type Auth struct {
+	Lockout  Lockout        `yaml:"lockout"`
+	Password PasswordPolicy `yaml:"password"`
+	Hashing  Hashing        `yaml:"hashing"`
+	Reset    PasswordReset  `yaml:"password_reset"`
+
+	InvitationTTL time.Duration `yaml:"invitation_ttl"`
+
+	// DummyLogin включает /dummyLogin для разработки и тестов. Когда выключен, ручка не регистрируется,
+	// а токены без пользователя, выданные ею раньше, не принимаются
+	DummyLogin bool `yaml:"dummy_login"`
+}
+
+type PasswordReset struct {
+	TokenTTL time.Duration `yaml:"token_ttl"`
+	Notifier Notifier      `yaml:"notifier"`
+}
+
+// Notifier: kind = log | file, path нужен только для file
+type Notifier struct {
+	Kind string `yaml:"kind"`
+	Path string `yaml:"path"`
+}
+
+// PasswordPolicy задаёт требования к паролю при регистрации
+type PasswordPolicy struct {
+	MinLength      int    `yaml:"min_length"`
+	MaxLength      int    `yaml:"max_length"`
+	RequireUpper   bool   `yaml:"require_upper"`
+	RequireLower   bool   `yaml:"require_lower"`
+	RequireDigit   bool   `yaml:"require_digit"`
+	RequireSpecial bool   `yaml:"require_special"`
+	DenylistPath   string `yaml:"denylist_path"`
+}
+
+// Hashing задаёт алгоритм хеширования паролей: bcrypt или argon2id.
+// При смене алгоритма или параметров пароль перехешируется при следующем входе
+type Hashing struct {
+	Algorithm     string `yaml:"algorithm"`
+	BcryptCost    int    `yaml:"bcrypt_cost"`
+	Argon2Time    uint32 `yaml:"argon2_time"`
+	Argon2Memory  uint32 `yaml:"argon2_memory"`
+	Argon2Threads uint8  `yaml:"argon2_threads"`
+}
+
+// Lockout описывает защиту /login от перебора паролей.
+// Нулевые значения отключают соответствующее ограничение
+type Lockout struct {
+	MaxFailures      int           `yaml:"max_failures"`
+	MaxFailuresPerIP int           `yaml:"max_failures_per_ip"`
+	LockoutDuration  time.Duration `yaml:"lockout_duration"`
+	BaseDelay        time.Duration `yaml:"base_delay"`
+	MaxDelay         time.Duration `yaml:"max_delay"`
+	ResetAfter       time.Duration `yaml:"reset_after"`
+}
+
 type GRPC struct {
 	Port string `yaml:"port"`
 }
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+type APIKeyCreateRequest struct {
+	Name      string        `json:"name"`
+	UserID    string        `json:"userId"`
+	Scopes    []model.Scope `json:"scopes"`
+	ExpiresAt *time.Time    `json:"expiresAt"`
+}
+
+// APIKeyCreateResponse содержит сам ключ, он показывается только один раз
+type APIKeyCreateResponse struct {
+	model.APIKey
+	Key string `json:"key"`
+}
+
+func NewAPIKeyHandler(sAKS service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: sAKS,
+	}
+}
+
+func (akh *APIKeyHandler) Create(ctx echo.Context) error {
+	var request APIKeyCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Name == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Name is required"})
+	}
+
+	if request.UserID == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "User id is required"})
+	}
+
+	userID, err := parseUUID(request.UserID, "Invalid user id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	key, raw, err := akh.service.Create(actorID(ctx), request.Name, userID, request.Scopes, request.ExpiresAt)
+	if err != nil {
+		return apiKeyError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, APIKeyCreateResponse{APIKey: *key, Key: raw})
+}
+
+func (akh *APIKeyHandler) List(ctx echo.Context) error {
+	keys, err := akh.service.List()
+	if err != nil {
+		return apiKeyError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, keys)
+}
+
+func (akh *APIKeyHandler) Revoke(ctx echo.Context) error {
+	id, err := parseUUID(ctx.Param("keyId"), "Invalid key id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if err := akh.service.Revoke(id); err != nil {
+		return apiKeyError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func apiKeyError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidScope):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Scopes must be a non-empty subset of 'pvz:read', 'pvz:write', 'reception:write'"})
+	case deferr.Is(err, service.ErrExpiresInPast):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Expiration must be in the future"})
+	case deferr.Is(err, service.ErrInactiveKeyUser):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "User is disabled"})
+	case deferr.Is(err, service.ErrUserNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "User not found"})
+	case deferr.Is(err, service.ErrAPIKeyNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "API key not found"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process API key"})
+	}
+}
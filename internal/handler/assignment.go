@@ -0,0 +1,113 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type AssignmentHandler struct {
+	service service.AssignmentService
+}
+
+type AssignmentCreateRequest struct {
+	UserID string `json:"userId"`
+}
+
+func NewAssignmentHandler(sAS service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		service: sAS,
+	}
+}
+
+func (ah *AssignmentHandler) Assign(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request AssignmentCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.UserID == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "User id is required"})
+	}
+
+	userID, err := parseUUID(request.UserID, "Invalid user id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if err := ah.service.Assign(actorID(ctx), userID, pvzID); err != nil {
+		return assignmentError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusCreated)
+}
+
+func (ah *AssignmentHandler) Unassign(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	userID, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if err := ah.service.Unassign(userID, pvzID); err != nil {
+		return assignmentError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (ah *AssignmentHandler) ListByPvz(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	assignments, err := ah.service.ListByPvz(pvzID)
+	if err != nil {
+		return assignmentError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, assignments)
+}
+
+func (ah *AssignmentHandler) ListByUser(ctx echo.Context) error {
+	userID, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	assignments, err := ah.service.ListByUser(userID)
+	if err != nil {
+		return assignmentError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, assignments)
+}
+
+func assignmentError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrPvzNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "PVZ not found"})
+	case deferr.Is(err, service.ErrUserNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "User not found"})
+	case deferr.Is(err, service.ErrAssignmentNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "Assignment not found"})
+	case deferr.Is(err, service.ErrNotEmployee):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Only employees can be assigned to PVZ"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process assignment"})
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type CellHandler struct {
+	service service.CellService
+}
+
+type CellCreateRequest struct {
+	Code     string `json:"code"`
+	Capacity int    `json:"capacity"`
+}
+
+type CellUpdateRequest struct {
+	Capacity int `json:"capacity"`
+}
+
+type ProductMoveRequest struct {
+	CellCode string `json:"cellCode"`
+}
+
+func NewCellHandler(sCS service.CellService) *CellHandler {
+	return &CellHandler{
+		service: sCS,
+	}
+}
+
+func (ch *CellHandler) List(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	cells, err := ch.service.List(pvzID)
+	if err != nil {
+		return cellError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, cells)
+}
+
+func (ch *CellHandler) Create(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request CellCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Code == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Code is required"})
+	}
+
+	cell, err := ch.service.Create(pvzID, request.Code, request.Capacity)
+	if err != nil {
+		return cellError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, cell)
+}
+
+func (ch *CellHandler) Update(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	cellID, err := parseUUID(ctx.Param("cellId"), "Invalid cell id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request CellUpdateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	cell, err := ch.service.UpdateCapacity(pvzID, cellID, request.Capacity)
+	if err != nil {
+		return cellError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, cell)
+}
+
+func (ch *CellHandler) Move(ctx echo.Context) error {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request ProductMoveRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.CellCode == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Cell code is required"})
+	}
+
+	location, err := ch.service.Move(actorID(ctx), productID, request.CellCode)
+	if err != nil {
+		return cellError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, location)
+}
+
+func (ch *CellHandler) Locate(ctx echo.Context) error {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	location, err := ch.service.Locate(productID)
+	if err != nil {
+		return cellError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, location)
+}
+
+func cellError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidCellCode):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Code must be 1-16 latin letters, digits or hyphens"})
+	case deferr.Is(err, service.ErrInvalidCellCapacity):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Capacity must be between 1 and 1000"})
+	case deferr.Is(err, service.ErrCapacityBelowUsage):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Capacity is below the number of products in the cell"})
+	case deferr.Is(err, service.ErrCellExists):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Storage cell with this code already exists"})
+	case deferr.Is(err, service.ErrCellNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "Storage cell not found"})
+	case deferr.Is(err, service.ErrCellFull):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Storage cell is full"})
+	default:
+		return productError(ctx, err)
+	}
+}
@@ -0,0 +1,157 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCellID = "9f8e7d6c-5b4a-4392-8817-6e5d4c3b2a19"
+
+func TestCellCreate_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockCellService *mocks.MockCellService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_code",
+			requestBody:    map[string]interface{}{"capacity": 10},
+			setupMock:      func(MockCellService *mocks.MockCellService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Code is required"},
+		},
+		{
+			name:        "invalid_capacity",
+			requestBody: map[string]interface{}{"code": "A-1", "capacity": 0},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Create", testPvzID, "A-1", 0).Return(nil, service.ErrInvalidCellCapacity)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Capacity must be between 1 and 1000"},
+		},
+		{
+			name:        "duplicate_code",
+			requestBody: map[string]interface{}{"code": "A-1", "capacity": 10},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Create", testPvzID, "A-1", 10).Return(nil, service.ErrCellExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Storage cell with this code already exists"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]interface{}{"code": "a-1", "capacity": 10},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Create", testPvzID, "a-1", 10).
+					Return(&model.StorageCell{ID: testCellID, PvzID: testPvzID, Code: "A-1", Capacity: 10}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"id": testCellID, "code": "A-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockCellService := new(mocks.MockCellService)
+			tc.setupMock(MockCellService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/pvz/"+testPvzID+"/cells", "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := handler.NewCellHandler(MockCellService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockCellService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductMove_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockCellService *mocks.MockCellService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_cell_code",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockCellService *mocks.MockCellService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Cell code is required"},
+		},
+		{
+			name:        "unknown_cell",
+			requestBody: map[string]string{"cellCode": "Z-9"},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Move", testUserID, testProductID, "Z-9").Return(nil, service.ErrCellNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "Storage cell not found"},
+		},
+		{
+			name:        "cell_full",
+			requestBody: map[string]string{"cellCode": "A-1"},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Move", testUserID, testProductID, "A-1").Return(nil, service.ErrCellFull)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Storage cell is full"},
+		},
+		{
+			name:        "product_issued",
+			requestBody: map[string]string{"cellCode": "A-1"},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Move", testUserID, testProductID, "A-1").Return(nil, service.ErrProductStatusTransition)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Product status does not allow this operation"},
+		},
+		{
+			name:        "successful_move",
+			requestBody: map[string]string{"cellCode": "A-1"},
+			setupMock: func(MockCellService *mocks.MockCellService) {
+				MockCellService.On("Move", testUserID, testProductID, "A-1").Return(&model.ProductLocation{
+					Product: model.Product{ID: testProductID, CellID: testCellID},
+					PvzID:   testPvzID,
+					Cell:    &model.StorageCell{ID: testCellID, Code: "A-1"},
+					Moves:   []model.CellMove{{ProductID: testProductID, ToCellID: testCellID}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"pvzId": testPvzID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockCellService := new(mocks.MockCellService)
+			tc.setupMock(MockCellService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/products/"+testProductID+"/move", tc.requestBody)
+			c.SetParamNames("productId")
+			c.SetParamValues(testProductID)
+
+			err := handler.NewCellHandler(MockCellService).Move(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockCellService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type CityHandler struct {
+	service service.CityService
+}
+
+type CityRequest struct {
+	Name string `json:"name"`
+}
+
+func NewCityHandler(sCS service.CityService) *CityHandler {
+	return &CityHandler{
+		service: sCS,
+	}
+}
+
+// List по умолчанию отдаёт только активные города, ?all=true - все
+func (ch *CityHandler) List(ctx echo.Context) error {
+	all := false
+	if raw := ctx.QueryParam("all"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "All must be a boolean"})
+		}
+		all = value
+	}
+
+	cities, err := ch.service.List(!all)
+	if err != nil {
+		return cityError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, cities)
+}
+
+func (ch *CityHandler) Create(ctx echo.Context) error {
+	var request CityRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	city, err := ch.service.Create(request.Name)
+	if err != nil {
+		return cityError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, city)
+}
+
+func (ch *CityHandler) Rename(ctx echo.Context) error {
+	id, err := cityIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request CityRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	city, err := ch.service.Rename(actorID(ctx), id, request.Name)
+	if err != nil {
+		return cityError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, city)
+}
+
+func (ch *CityHandler) Activate(ctx echo.Context) error {
+	return ch.setActive(ctx, true)
+}
+
+func (ch *CityHandler) Deactivate(ctx echo.Context) error {
+	return ch.setActive(ctx, false)
+}
+
+func (ch *CityHandler) setActive(ctx echo.Context, active bool) error {
+	id, err := cityIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	city, err := ch.service.SetActive(id, active)
+	if err != nil {
+		return cityError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, city)
+}
+
+func (ch *CityHandler) History(ctx echo.Context) error {
+	id, err := cityIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	renames, err := ch.service.History(id)
+	if err != nil {
+		return cityError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, renames)
+}
+
+func cityIDParam(ctx echo.Context) (string, error) {
+	return parseUUID(ctx.Param("cityId"), "Invalid city id")
+}
+
+func cityError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidCityName):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "City name must be 1-100 characters long"})
+	case deferr.Is(err, service.ErrCityExists):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "City already exists"})
+	case deferr.Is(err, service.ErrCityNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "City not found"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process city"})
+	}
+}
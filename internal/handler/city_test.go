@@ -0,0 +1,141 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCityID = "0b9c8d7e-6f5a-4b3c-9d2e-1f0a9b8c7d6e"
+
+func TestCityRename_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cityID         string
+		requestBody    interface{}
+		setupMock      func(MockCityService *mocks.MockCityService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "invalid_city_id",
+			cityID:         "42",
+			requestBody:    map[string]string{"name": "Екатеринбург"},
+			setupMock:      func(MockCityService *mocks.MockCityService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid city id"},
+		},
+		{
+			name:        "empty_name",
+			cityID:      testCityID,
+			requestBody: map[string]string{"name": " "},
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("Rename", testModeratorID, testCityID, " ").Return(nil, service.ErrInvalidCityName)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "City name must be 1-100 characters long"},
+		},
+		{
+			name:        "name_taken",
+			cityID:      testCityID,
+			requestBody: map[string]string{"name": "Казань"},
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("Rename", testModeratorID, testCityID, "Казань").Return(nil, service.ErrCityExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "City already exists"},
+		},
+		{
+			name:        "city_not_found",
+			cityID:      testCityID,
+			requestBody: map[string]string{"name": "Екатеринбург"},
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("Rename", testModeratorID, testCityID, "Екатеринбург").Return(nil, service.ErrCityNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "City not found"},
+		},
+		{
+			name:        "successful_rename",
+			cityID:      testCityID,
+			requestBody: map[string]string{"name": "Екатеринбург"},
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("Rename", testModeratorID, testCityID, "Екатеринбург").
+					Return(&model.City{ID: testCityID, Name: "Екатеринбург", Active: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"name": "Екатеринбург"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockCityService := new(mocks.MockCityService)
+			tc.setupMock(MockCityService)
+
+			c, rec := newUserAdminContext(http.MethodPatch, "/cities/"+tc.cityID, "", tc.requestBody)
+			c.SetParamNames("cityId")
+			c.SetParamValues(tc.cityID)
+
+			err := handler.NewCityHandler(MockCityService).Rename(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockCityService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCityList_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockCityService *mocks.MockCityService)
+		expectedStatus int
+	}{
+		{
+			name:           "invalid_all",
+			query:          "?all=maybe",
+			setupMock:      func(MockCityService *mocks.MockCityService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "active_by_default",
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("List", true).Return([]model.City{{Name: "Москва", Active: true}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "all_cities",
+			query: "?all=true",
+			setupMock: func(MockCityService *mocks.MockCityService) {
+				MockCityService.On("List", false).Return([]model.City{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockCityService := new(mocks.MockCityService)
+			tc.setupMock(MockCityService)
+
+			c, rec := newUserAdminContext(http.MethodGet, "/cities"+tc.query, "", nil)
+
+			err := handler.NewCityHandler(MockCityService).List(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+
+			MockCityService.AssertExpectations(t)
+		})
+	}
+}
@@ -3,13 +3,16 @@ package handler
 import (
 	"log/slog"
 
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/notifier"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
 	"github.com/labstack/echo/v4"
 )
 
-func New(log *slog.Logger, db repository.Database, jwtSecret []byte) *echo.Echo {
+func New(log *slog.Logger, db repository.Database, cfg *config.Config) (*echo.Echo, error) {
 	e := echo.New()
 
 	e.Use(middleware.Logging(log))
@@ -17,17 +20,146 @@ func New(log *slog.Logger, db repository.Database, jwtSecret []byte) *echo.Echo
 	e.HTTPErrorHandler = middleware.ErrorHandler(log)
 
 	// Service
-	userService := service.NewUserService(db, jwtSecret)
+	passwordPolicy, err := service.NewPasswordPolicy(cfg.Auth.Password, cfg.Auth.Hashing)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := service.NewPasswordHasher(cfg.Auth.Hashing)
+	if err != nil {
+		return nil, err
+	}
+
+	resetNotifier, err := notifier.New(cfg.Auth.Reset.Notifier, log)
+	if err != nil {
+		return nil, err
+	}
+
+	userService := service.NewUserService(db, []byte(cfg.HTTP.JWTSecret), cfg.Auth, passwordPolicy, passwordHasher, resetNotifier)
+	userAdminService := service.NewUserAdminService(db)
+	invitationService := service.NewInvitationService(db, cfg.Auth.InvitationTTL)
+	assignmentService := service.NewAssignmentService(db)
+	apiKeyService := service.NewAPIKeyService(db)
+	permissionService := service.NewPermissionService(db)
+	receptionService := service.NewReceptionService(db, cfg.Receptions, permissionService)
+	loginAuditService := service.NewLoginAuditService(db)
+	pvzService := service.NewPvzService(db)
+	cityService := service.NewCityService(db)
+	productTypeService := service.NewProductTypeService(db)
+	reportService := service.NewReportService(db)
+	productService := service.NewProductService(db)
+	returnService := service.NewReturnService(db)
+	cellService := service.NewCellService(db)
 
 	// Handler
 	userHandler := NewUserHandler(userService)
-	pvzHandler := NewPvzHandler(log)
+	userAdminHandler := NewUserAdminHandler(userAdminService)
+	invitationHandler := NewInvitationHandler(invitationService)
+	pvzHandler := NewPvzHandler(pvzService)
+	cityHandler := NewCityHandler(cityService)
+	productTypeHandler := NewProductTypeHandler(productTypeService)
+	reportHandler := NewReportHandler(reportService)
+	receptionHandler := NewReceptionHandler(receptionService)
+	productHandler := NewProductHandler(productService)
+	returnHandler := NewReturnHandler(returnService)
+	cellHandler := NewCellHandler(cellService)
+	assignmentHandler := NewAssignmentHandler(assignmentService)
+	apiKeyHandler := NewAPIKeyHandler(apiKeyService)
+	roleHandler := NewRoleHandler(permissionService)
+	loginAuditHandler := NewLoginAuditHandler(loginAuditService)
+
+	// Middleware
+	auth := middleware.Auth(userService)
+	receptionAuth := middleware.AuthWithAPIKey(userService, apiKeyService, model.ScopeReceptionWrite)
+	pvzReadAuth := middleware.AuthWithAPIKey(userService, apiKeyService, model.ScopePvzRead)
+	pvzWriteAuth := middleware.AuthWithAPIKey(userService, apiKeyService, model.ScopePvzWrite)
+	can := func(permission model.Permission) echo.MiddlewareFunc {
+		return middleware.RequirePermission(permissionService, permission)
+	}
 
-	e.POST("/dummyLogin", userHandler.DummyLogin)
+	// Тестовые токены выдаются только в окружениях разработки
+	if cfg.Auth.DummyLogin {
+		e.POST("/dummyLogin", userHandler.DummyLogin)
+	}
 	e.POST("/register", userHandler.Register)
 	e.POST("/login", userHandler.Login)
+	e.POST("/token/refresh", userHandler.Refresh)
+
+	e.GET("/sessions", userHandler.Sessions, auth)
+	e.GET("/login-history", loginAuditHandler.History, auth, can(model.PermAuditView))
+
+	e.POST("/password/change", userHandler.ChangePassword, auth)
+	e.POST("/password/reset/request", userHandler.RequestPasswordReset)
+	e.POST("/password/reset/confirm", userHandler.ResetPassword)
+
+	e.POST("/users/unlock", userHandler.Unlock, auth, can(model.PermUserManage))
+	e.GET("/users", userAdminHandler.List, auth, can(model.PermUserManage))
+	e.GET("/users/:userId", userAdminHandler.Get, auth, can(model.PermUserManage))
+	e.POST("/users/:userId/role", userAdminHandler.ChangeRole, auth, can(model.PermUserManage))
+	e.POST("/users/:userId/disable", userAdminHandler.Disable, auth, can(model.PermUserManage))
+	e.POST("/users/:userId/enable", userAdminHandler.Enable, auth, can(model.PermUserManage))
+	e.DELETE("/users/:userId", userAdminHandler.Delete, auth, can(model.PermUserManage))
+
+	e.POST("/invitations", invitationHandler.Create, auth, can(model.PermInvitationCreate))
+
+	e.GET("/cities", cityHandler.List, auth)
+	e.POST("/cities", cityHandler.Create, auth, can(model.PermCityManage))
+	e.PATCH("/cities/:cityId", cityHandler.Rename, auth, can(model.PermCityManage))
+	e.POST("/cities/:cityId/activate", cityHandler.Activate, auth, can(model.PermCityManage))
+	e.POST("/cities/:cityId/deactivate", cityHandler.Deactivate, auth, can(model.PermCityManage))
+	e.GET("/cities/:cityId/history", cityHandler.History, auth, can(model.PermCityManage))
+
+	e.GET("/product-types", productTypeHandler.List, auth)
+	e.POST("/product-types", productTypeHandler.Create, auth, can(model.PermProductTypeManage))
+	e.PATCH("/product-types/:code", productTypeHandler.Rename, auth, can(model.PermProductTypeManage))
+	e.POST("/product-types/:code/activate", productTypeHandler.Activate, auth, can(model.PermProductTypeManage))
+	e.POST("/product-types/:code/deactivate", productTypeHandler.Deactivate, auth, can(model.PermProductTypeManage))
+
+	e.POST("/pvz", pvzHandler.Create, pvzWriteAuth, can(model.PermPvzCreate))
+	e.GET("/pvz", pvzHandler.List, pvzReadAuth, can(model.PermPvzRead))
+	e.GET("/pvz/nearby", pvzHandler.Nearby, pvzReadAuth, can(model.PermPvzRead))
+	e.PATCH("/pvz/:pvzId", pvzHandler.Update, pvzWriteAuth, can(model.PermPvzManage))
+	e.PUT("/pvz/:pvzId/capacity", pvzHandler.SetCapacity, pvzWriteAuth, can(model.PermPvzManage))
+	e.GET("/pvz/:pvzId/hours", pvzHandler.Schedule, pvzReadAuth, can(model.PermPvzRead))
+	e.PUT("/pvz/:pvzId/hours", pvzHandler.SetSchedule, pvzWriteAuth, can(model.PermPvzManage))
+	e.POST("/pvz/:pvzId/suspend", pvzHandler.Suspend, pvzWriteAuth, can(model.PermPvzManage))
+	e.POST("/pvz/:pvzId/activate", pvzHandler.Activate, pvzWriteAuth, can(model.PermPvzManage))
+	e.POST("/pvz/:pvzId/close", pvzHandler.Close, pvzWriteAuth, can(model.PermPvzManage))
+	e.GET("/pvz/:pvzId/products", productHandler.List, pvzReadAuth, can(model.PermPvzRead))
+	e.GET("/pvz/:pvzId/cells", cellHandler.List, pvzReadAuth, can(model.PermPvzRead))
+	e.POST("/pvz/:pvzId/cells", cellHandler.Create, pvzWriteAuth, can(model.PermCellManage))
+	e.PATCH("/pvz/:pvzId/cells/:cellId", cellHandler.Update, pvzWriteAuth, can(model.PermCellManage))
+	e.POST("/pvz/:pvzId/close_last_reception", receptionHandler.CloseLastReception, receptionAuth, can(model.PermReceptionClose))
+	e.POST("/pvz/:pvzId/reopen_last_reception", receptionHandler.ReopenLastReception, auth, can(model.PermReceptionReopen))
+	e.GET("/receptions/:receptionId/events", receptionHandler.Events, auth, can(model.PermAuditView))
+	e.POST("/pvz/:pvzId/delete_last_product", receptionHandler.DeleteLastProduct, receptionAuth, can(model.PermProductDelete))
+
+	e.GET("/pvz/:pvzId/employees", assignmentHandler.ListByPvz, auth, can(model.PermAssignmentManage))
+	e.POST("/pvz/:pvzId/employees", assignmentHandler.Assign, auth, can(model.PermAssignmentManage))
+	e.DELETE("/pvz/:pvzId/employees/:userId", assignmentHandler.Unassign, auth, can(model.PermAssignmentManage))
+	e.GET("/users/:userId/pvz", assignmentHandler.ListByUser, auth, can(model.PermAssignmentManage))
+
+	e.POST("/receptions", receptionHandler.Create, receptionAuth, can(model.PermReceptionOpen))
+	e.POST("/products", receptionHandler.AddProduct, receptionAuth, can(model.PermProductAdd))
+	e.GET("/products/barcode/:barcode", productHandler.History, pvzReadAuth, can(model.PermPvzRead))
+	e.POST("/products/:productId/ready", productHandler.MarkReady, receptionAuth, can(model.PermProductIssue))
+	e.POST("/products/:productId/issue", productHandler.Issue, receptionAuth, can(model.PermProductIssue))
+	e.POST("/products/:productId/return", returnHandler.Return, receptionAuth, can(model.PermProductIssue))
+	e.POST("/products/:productId/move", cellHandler.Move, receptionAuth, can(model.PermProductMove))
+	e.GET("/products/:productId/location", cellHandler.Locate, pvzReadAuth, can(model.PermPvzRead))
+
+	e.POST("/return-batches", returnHandler.OpenBatch, receptionAuth, can(model.PermProductIssue))
+	e.POST("/pvz/:pvzId/close_last_return_batch", returnHandler.CloseLastBatch, receptionAuth, can(model.PermProductIssue))
+
+	e.GET("/reports/receptions/daily", reportHandler.DailyReceptions, auth, can(model.PermReportView))
+
+	e.GET("/api-keys", apiKeyHandler.List, auth, can(model.PermAPIKeyManage))
+	e.POST("/api-keys", apiKeyHandler.Create, auth, can(model.PermAPIKeyManage))
+	e.DELETE("/api-keys/:keyId", apiKeyHandler.Revoke, auth, can(model.PermAPIKeyManage))
 
-	e.POST("/pvz", pvzHandler.Create)
+	e.GET("/roles", roleHandler.List, auth, can(model.PermRoleManage))
+	e.POST("/roles", roleHandler.Create, auth, can(model.PermRoleManage))
+	e.PUT("/roles/:role/permissions", roleHandler.SetPermissions, auth, can(model.PermRoleManage))
 
-	return e
+	return e, nil
 }
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime/types"
+)
+
+type InvitationHandler struct {
+	service service.InvitationService
+}
+
+type InvitationCreateRequest struct {
+	Email types.Email `json:"email"`
+	Role  string      `json:"role"`
+}
+
+// InvitationCreateResponse содержит код приглашения, он показывается только один раз
+type InvitationCreateResponse struct {
+	ID        string           `json:"id"`
+	Email     string           `json:"email"`
+	Role      openapi.UserRole `json:"role"`
+	Code      string           `json:"code"`
+	ExpiresAt time.Time        `json:"expiresAt"`
+}
+
+func NewInvitationHandler(sIS service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		service: sIS,
+	}
+}
+
+func (ih *InvitationHandler) Create(ctx echo.Context) error {
+	var request InvitationCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		if deferr.Is(err, types.ErrValidationEmail) {
+			return errors.InvalidEmail()
+		}
+
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Email == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Email is required"})
+	}
+
+	if request.Role == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role is required"})
+	}
+
+	invitation, code, err := ih.service.Create(actorID(ctx), string(request.Email), model.UserRole(request.Role))
+	if deferr.Is(err, service.ErrUnknownRole) {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown role"})
+	} else if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to create invitation"})
+	}
+
+	return ctx.JSON(http.StatusCreated, InvitationCreateResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      openapi.UserRole(invitation.Role),
+		Code:      code,
+		ExpiresAt: invitation.ExpiresAt,
+	})
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationCreate_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockInvitationService *mocks.MockInvitationService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_role",
+			requestBody:    map[string]string{"email": "new@test.com"},
+			setupMock:      func(MockInvitationService *mocks.MockInvitationService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Role is required"},
+		},
+		{
+			name:        "invalid_role",
+			requestBody: map[string]string{"email": "new@test.com", "role": "admin"},
+			setupMock: func(MockInvitationService *mocks.MockInvitationService) {
+				MockInvitationService.On("Create", testModeratorID, "new@test.com", model.UserRole("admin")).
+					Return(nil, "", service.ErrUnknownRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Unknown role"},
+		},
+		{
+			name:        "database_error",
+			requestBody: map[string]string{"email": "new@test.com", "role": "moderator"},
+			setupMock: func(MockInvitationService *mocks.MockInvitationService) {
+				MockInvitationService.On("Create", testModeratorID, "new@test.com", model.RoleModerator).
+					Return(nil, "", fmt.Errorf("DB connect failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"message": "Failed to create invitation"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]string{"email": "new@test.com", "role": "moderator"},
+			setupMock: func(MockInvitationService *mocks.MockInvitationService) {
+				MockInvitationService.On("Create", testModeratorID, "new@test.com", model.RoleModerator).
+					Return(&model.Invitation{ID: testUserID, Email: "new@test.com", Role: model.RoleModerator, ExpiresAt: time.Now()}, "secret_code", nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"email": "new@test.com", "role": "moderator", "code": "secret_code"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockInvitationService := new(mocks.MockInvitationService)
+			tc.setupMock(MockInvitationService)
+
+			handler := handler.NewInvitationHandler(MockInvitationService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/invitations", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.Set(middleware.ContextUserID, testModeratorID)
+
+			err := handler.Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockInvitationService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type LoginAuditHandler struct {
+	service service.LoginAuditService
+}
+
+func NewLoginAuditHandler(sLAS service.LoginAuditService) *LoginAuditHandler {
+	return &LoginAuditHandler{
+		service: sLAS,
+	}
+}
+
+func (lah *LoginAuditHandler) History(ctx echo.Context) error {
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	filter := model.LoginAttemptFilter{
+		Email: ctx.QueryParam("email"),
+		IP:    ctx.QueryParam("ip"),
+		Kind:  model.LoginKind(ctx.QueryParam("kind")),
+	}
+
+	if raw := ctx.QueryParam("userId"); raw != "" {
+		if filter.UserID, err = parseUUID(raw, "Invalid user id"); err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+		}
+	}
+
+	switch filter.Kind {
+	case "", model.LoginPassword, model.LoginDummy, model.LoginRefresh:
+	default:
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Kind must be 'login', 'dummy_login' or 'refresh'"})
+	}
+
+	if raw := ctx.QueryParam("success"); raw != "" {
+		success, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Success must be a boolean"})
+		}
+		filter.Success = &success
+	}
+
+	if filter.From, err = parseTimeParam(ctx, "from"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if filter.To, err = parseTimeParam(ctx, "to"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	attempts, err := lah.service.History(filter, page, limit)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to list login history"})
+	}
+
+	return ctx.JSON(http.StatusOK, attempts)
+}
@@ -0,0 +1,96 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginHistory_TableDriven(t *testing.T) {
+	failed := false
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockLoginAuditService *mocks.MockLoginAuditService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "invalid_kind",
+			query:          "?kind=sso",
+			setupMock:      func(MockLoginAuditService *mocks.MockLoginAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Kind must be 'login', 'dummy_login' or 'refresh'"},
+		},
+		{
+			name:           "invalid_success",
+			query:          "?success=maybe",
+			setupMock:      func(MockLoginAuditService *mocks.MockLoginAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Success must be a boolean"},
+		},
+		{
+			name:           "invalid_from",
+			query:          "?from=yesterday",
+			setupMock:      func(MockLoginAuditService *mocks.MockLoginAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "from must be a date-time in RFC 3339 format"},
+		},
+		{
+			name:           "invalid_user_id",
+			query:          "?userId=42",
+			setupMock:      func(MockLoginAuditService *mocks.MockLoginAuditService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid user id"},
+		},
+		{
+			name:  "database_error",
+			query: "",
+			setupMock: func(MockLoginAuditService *mocks.MockLoginAuditService) {
+				MockLoginAuditService.On("History", model.LoginAttemptFilter{}, 1, 10).
+					Return(nil, fmt.Errorf("DB connect failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"message": "Failed to list login history"},
+		},
+		{
+			name:  "filtered_history",
+			query: "?userId=" + testUserID + "&ip=" + testIP + "&kind=login&success=false&from=2025-04-01T00:00:00Z&page=2&limit=5",
+			setupMock: func(MockLoginAuditService *mocks.MockLoginAuditService) {
+				MockLoginAuditService.On("History", model.LoginAttemptFilter{
+					UserID:  testUserID,
+					IP:      testIP,
+					Kind:    model.LoginPassword,
+					Success: &failed,
+					From:    &from,
+				}, 2, 5).Return([]model.LoginAttempt{{Kind: model.LoginPassword, Reason: model.LoginReasonInvalidPassword}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockLoginAuditService := new(mocks.MockLoginAuditService)
+			tc.setupMock(MockLoginAuditService)
+
+			c, rec := newUserAdminContext(http.MethodGet, "/login-history"+tc.query, "", nil)
+
+			err := handler.NewLoginAuditHandler(MockLoginAuditService).History(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockLoginAuditService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// parsePagination читает page и limit из query, подставляя значения по умолчанию
+func parsePagination(ctx echo.Context) (int, int, error) {
+	page, limit := 1, service.DefaultPageLimit
+
+	if raw := ctx.QueryParam("page"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+		page = value
+	}
+
+	if raw := ctx.QueryParam("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > service.MaxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", service.MaxPageLimit)
+		}
+		limit = value
+	}
+
+	return page, limit, nil
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	deferr "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// parseUUID возвращает id в каноническом виде или ошибку с переданным сообщением
+func parseUUID(value string, message string) (string, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return "", deferr.New(message)
+	}
+
+	return id.String(), nil
+}
+
+func pvzIDParam(ctx echo.Context) (string, error) {
+	return parseUUID(ctx.Param("pvzId"), "Invalid pvz id")
+}
+
+// parseTimeParam читает необязательный параметр в формате RFC 3339
+func parseTimeParam(ctx echo.Context, name string) (*time.Time, error) {
+	raw := ctx.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date-time in RFC 3339 format", name)
+	}
+
+	return &value, nil
+}
+
+// parseDateParam читает необязательный параметр-дату в формате YYYY-MM-DD
+func parseDateParam(ctx echo.Context, name string) (*time.Time, error) {
+	raw := ctx.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date in YYYY-MM-DD format", name)
+	}
+
+	return &value, nil
+}
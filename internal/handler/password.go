@@ -0,0 +1,117 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime/types"
+)
+
+type PasswordChangeRequest struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+type PasswordResetRequest struct {
+	Email types.Email `json:"email"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (u *UserHandler) ChangePassword(ctx echo.Context) error {
+	// Токены из /dummyLogin не привязаны к пользователю
+	userID, _ := ctx.Get(middleware.ContextUserID).(string)
+	if userID == "" {
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Password change requires a registered user"})
+	}
+
+	var request PasswordChangeRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.OldPassword == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Old password is required"})
+	}
+
+	if request.NewPassword == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "New password is required"})
+	}
+
+	if err := u.service.ChangePassword(userID, request.OldPassword, request.NewPassword); err != nil {
+		return passwordError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (u *UserHandler) RequestPasswordReset(ctx echo.Context) error {
+	var request PasswordResetRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		if deferr.Is(err, types.ErrValidationEmail) {
+			return errors.InvalidEmail()
+		}
+
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Email == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Email is required"})
+	}
+
+	if err := u.service.RequestPasswordReset(string(request.Email)); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to request password reset"})
+	}
+
+	// Ответ одинаковый независимо от того, есть ли такой пользователь
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+func (u *UserHandler) ResetPassword(ctx echo.Context) error {
+	var request PasswordResetConfirmRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Token == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Token is required"})
+	}
+
+	if request.NewPassword == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "New password is required"})
+	}
+
+	if err := u.service.ResetPassword(request.Token, request.NewPassword); err != nil {
+		return passwordError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func passwordError(ctx echo.Context, err error) error {
+	var policyErr *service.PasswordPolicyError
+
+	switch {
+	case deferr.As(err, &policyErr):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: policyErr.Message})
+	case deferr.Is(err, service.ErrInvalidCurrentPassword):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Current password is incorrect"})
+	case deferr.Is(err, service.ErrInvalidResetToken):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reset token is invalid or expired"})
+	case deferr.Is(err, service.ErrUserNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "User not found"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to update password"})
+	}
+}
@@ -0,0 +1,205 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserID = "4f1c7a0e-7f5b-4c55-9d8e-2b1b5f0f3a11"
+
+func TestChangePassword_TableDriven(t *testing.T) {
+	testCases := []struct {
+		UserTestCase
+		userID string
+	}{
+		{
+			UserTestCase: UserTestCase{
+				name:           "dummy_token",
+				requestBody:    map[string]string{"oldPassword": "Old12345", "newPassword": "New12345"},
+				setupMock:      func(MockUserService *mocks.MockUserService) {},
+				expectedStatus: http.StatusForbidden,
+				expectedBody:   map[string]string{"message": "Password change requires a registered user"},
+			},
+		},
+		{
+			UserTestCase: UserTestCase{
+				name:           "missing_old_password",
+				requestBody:    map[string]string{"newPassword": "New12345"},
+				setupMock:      func(MockUserService *mocks.MockUserService) {},
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   map[string]string{"message": "Old password is required"},
+			},
+			userID: testUserID,
+		},
+		{
+			UserTestCase: UserTestCase{
+				name:           "missing_new_password",
+				requestBody:    map[string]string{"oldPassword": "Old12345"},
+				setupMock:      func(MockUserService *mocks.MockUserService) {},
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   map[string]string{"message": "New password is required"},
+			},
+			userID: testUserID,
+		},
+		{
+			UserTestCase: UserTestCase{
+				name:        "wrong_old_password",
+				requestBody: map[string]string{"oldPassword": "Wrong123", "newPassword": "New12345"},
+				setupMock: func(MockUserService *mocks.MockUserService) {
+					MockUserService.On("ChangePassword", testUserID, "Wrong123", "New12345").
+						Return(service.ErrInvalidCurrentPassword)
+				},
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   map[string]string{"message": "Current password is incorrect"},
+			},
+			userID: testUserID,
+		},
+		{
+			UserTestCase: UserTestCase{
+				name:        "weak_new_password",
+				requestBody: map[string]string{"oldPassword": "Old12345", "newPassword": "new"},
+				setupMock: func(MockUserService *mocks.MockUserService) {
+					MockUserService.On("ChangePassword", testUserID, "Old12345", "new").
+						Return(&service.PasswordPolicyError{Message: "Password must be at least 8 characters long"})
+				},
+				expectedStatus: http.StatusBadRequest,
+				expectedBody:   map[string]string{"message": "Password must be at least 8 characters long"},
+			},
+			userID: testUserID,
+		},
+		{
+			UserTestCase: UserTestCase{
+				name:        "successful_change",
+				requestBody: map[string]string{"oldPassword": "Old12345", "newPassword": "New12345"},
+				setupMock: func(MockUserService *mocks.MockUserService) {
+					MockUserService.On("ChangePassword", testUserID, "Old12345", "New12345").
+						Return(nil)
+				},
+				expectedStatus: http.StatusOK,
+			},
+			userID: testUserID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			tc.setupMock(MockUserService)
+
+			handler := handler.NewUserHandler(MockUserService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/password/change", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.Set(middleware.ContextUserID, tc.userID)
+
+			err := handler.ChangePassword(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResetPassword_TableDriven(t *testing.T) {
+	testCases := []UserTestCase{
+		{
+			name:           "missing_token",
+			requestBody:    map[string]string{"newPassword": "New12345"},
+			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Token is required"},
+		},
+		{
+			name:        "expired_token",
+			requestBody: map[string]string{"token": "expired", "newPassword": "New12345"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("ResetPassword", "expired", "New12345").
+					Return(service.ErrInvalidResetToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Reset token is invalid or expired"},
+		},
+		{
+			name:        "database_error",
+			requestBody: map[string]string{"token": "token", "newPassword": "New12345"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("ResetPassword", "token", "New12345").
+					Return(fmt.Errorf("DB connect failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"message": "Failed to update password"},
+		},
+		{
+			name:        "successful_reset",
+			requestBody: map[string]string{"token": "token", "newPassword": "New12345"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("ResetPassword", "token", "New12345").
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			tc.setupMock(MockUserService)
+
+			handler := handler.NewUserHandler(MockUserService)
+
+			reqBody, _ := json.Marshal(tc.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/password/reset/confirm", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+
+			err := handler.ResetPassword(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserService.AssertExpectations(t)
+		})
+	}
+}
+
+// assertMessage сверяет поля ответа с ожидаемыми, если они заданы
+func assertMessage(t *testing.T, rec *httptest.ResponseRecorder, expectedBody interface{}) {
+	t.Helper()
+
+	expectedMap, ok := expectedBody.(map[string]string)
+	if !ok {
+		return
+	}
+
+	var actualResponse map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &actualResponse))
+
+	for key, expectedValue := range expectedMap {
+		assert.Equal(t, expectedValue, actualResponse[key])
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	barcodeFormatMessage = "Barcode must be 6-32 latin letters or digits, hyphens allowed inside"
+	productStatusMessage = "Status must be one of 'received', 'ready', 'issued', 'returned'"
+)
+
+type ProductHandler struct {
+	service service.ProductService
+}
+
+type ProductIssueRequest struct {
+	Code string `json:"code"`
+}
+
+// ProductReadyResponse содержит код получателя, он показывается только один раз
+type ProductReadyResponse struct {
+	model.Product
+	Code string `json:"code"`
+}
+
+func NewProductHandler(sPS service.ProductService) *ProductHandler {
+	return &ProductHandler{
+		service: sPS,
+	}
+}
+
+func productIDParam(ctx echo.Context) (string, error) {
+	return parseUUID(ctx.Param("productId"), "Invalid product id")
+}
+
+func (ph *ProductHandler) History(ctx echo.Context) error {
+	history, err := ph.service.History(ctx.Param("barcode"))
+	if err != nil {
+		return productError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, history)
+}
+
+func (ph *ProductHandler) List(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	products, err := ph.service.List(pvzID, model.ProductStatus(ctx.QueryParam("status")), page, limit)
+	if err != nil {
+		return productError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, products)
+}
+
+func (ph *ProductHandler) MarkReady(ctx echo.Context) error {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	product, code, err := ph.service.MarkReady(actorID(ctx), productID)
+	if err != nil {
+		return productError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, ProductReadyResponse{Product: *product, Code: code})
+}
+
+func (ph *ProductHandler) Issue(ctx echo.Context) error {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request ProductIssueRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Code == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Code is required"})
+	}
+
+	product, err := ph.service.Issue(actorID(ctx), productID, request.Code)
+	if err != nil {
+		return productError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, product)
+}
+
+func productError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidBarcode):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: barcodeFormatMessage})
+	case deferr.Is(err, service.ErrUnknownProductStatus):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: productStatusMessage})
+	case deferr.Is(err, service.ErrProductNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "Product not found"})
+	case deferr.Is(err, service.ErrPvzNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "PVZ not found"})
+	case deferr.Is(err, service.ErrNotAssigned):
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Employee is not assigned to this PVZ"})
+	case deferr.Is(err, service.ErrReceptionNotClosed):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Product reception is not closed yet"})
+	case deferr.Is(err, service.ErrProductStatusTransition):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Product status does not allow this operation"})
+	case deferr.Is(err, service.ErrInvalidIssueCode):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid verification code"})
+	case deferr.Is(err, service.ErrIssueCodeLocked):
+		return ctx.JSON(http.StatusTooManyRequests, openapi.Error{Message: "Too many invalid codes, mark the product ready again to get a new code"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process product"})
+	}
+}
@@ -0,0 +1,231 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBarcode   = "4600000000017"
+	testProductID = "7e6d5c4b-3a29-4f18-8e7d-6c5b4a392817"
+)
+
+func TestProductHistory_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		barcode        string
+		setupMock      func(MockProductService *mocks.MockProductService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:    "invalid_barcode",
+			barcode: "ab",
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("History", "ab").Return(nil, service.ErrInvalidBarcode)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Barcode must be 6-32 latin letters or digits, hyphens allowed inside"},
+		},
+		{
+			name:    "successful_history",
+			barcode: testBarcode,
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("History", testBarcode).Return([]model.ProductHistoryEntry{{
+					Product:           model.Product{ID: testUserID, DateTime: time.Now(), Type: model.ProductShoes, ReceptionID: testReceptionID, Barcode: testBarcode},
+					PvzID:             testPvzID,
+					ReceptionDateTime: time.Now(),
+					ReceptionStatus:   model.ReceptionClose,
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockProductService := new(mocks.MockProductService)
+			tc.setupMock(MockProductService)
+
+			c, rec := newEmployeeContext(http.MethodGet, "/products/barcode/"+tc.barcode, nil)
+			c.SetParamNames("barcode")
+			c.SetParamValues(tc.barcode)
+
+			err := handler.NewProductHandler(MockProductService).History(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockProductService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductIssuance_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		action         string
+		productID      string
+		requestBody    interface{}
+		setupMock      func(MockProductService *mocks.MockProductService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "invalid_product_id",
+			action:         "ready",
+			productID:      "42",
+			setupMock:      func(MockProductService *mocks.MockProductService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid product id"},
+		},
+		{
+			name:      "ready_reception_open",
+			action:    "ready",
+			productID: testProductID,
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("MarkReady", testUserID, testProductID).Return(nil, "", service.ErrReceptionNotClosed)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Product reception is not closed yet"},
+		},
+		{
+			name:      "successful_ready",
+			action:    "ready",
+			productID: testProductID,
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("MarkReady", testUserID, testProductID).
+					Return(&model.Product{ID: testProductID, Status: model.ProductReady}, "042517", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "ready", "code": "042517"},
+		},
+		{
+			name:           "issue_missing_code",
+			action:         "issue",
+			productID:      testProductID,
+			requestBody:    map[string]string{},
+			setupMock:      func(MockProductService *mocks.MockProductService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Code is required"},
+		},
+		{
+			name:        "issue_invalid_code",
+			action:      "issue",
+			productID:   testProductID,
+			requestBody: map[string]string{"code": "000000"},
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("Issue", testUserID, testProductID, "000000").Return(nil, service.ErrInvalidIssueCode)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid verification code"},
+		},
+		{
+			name:        "issue_code_locked",
+			action:      "issue",
+			productID:   testProductID,
+			requestBody: map[string]string{"code": "000000"},
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("Issue", testUserID, testProductID, "000000").Return(nil, service.ErrIssueCodeLocked)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   map[string]string{"message": "Too many invalid codes, mark the product ready again to get a new code"},
+		},
+		{
+			name:        "successful_issue",
+			action:      "issue",
+			productID:   testProductID,
+			requestBody: map[string]string{"code": "042517"},
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("Issue", testUserID, testProductID, "042517").
+					Return(&model.Product{ID: testProductID, Status: model.ProductIssued}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "issued"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockProductService := new(mocks.MockProductService)
+			tc.setupMock(MockProductService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/products/"+tc.productID+"/"+tc.action, tc.requestBody)
+			c.SetParamNames("productId")
+			c.SetParamValues(tc.productID)
+
+			h := handler.NewProductHandler(MockProductService)
+
+			var err error
+			switch tc.action {
+			case "ready":
+				err = h.MarkReady(c)
+			case "issue":
+				err = h.Issue(c)
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockProductService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductList_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockProductService *mocks.MockProductService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "unknown_status",
+			query: "?status=lost",
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("List", testPvzID, model.ProductStatus("lost"), 1, service.DefaultPageLimit).
+					Return(nil, service.ErrUnknownProductStatus)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Status must be one of 'received', 'ready', 'issued', 'returned'"},
+		},
+		{
+			name:  "ready_products",
+			query: "?status=ready&page=2",
+			setupMock: func(MockProductService *mocks.MockProductService) {
+				MockProductService.On("List", testPvzID, model.ProductReady, 2, service.DefaultPageLimit).
+					Return([]model.Product{{ID: testProductID, Status: model.ProductReady}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockProductService := new(mocks.MockProductService)
+			tc.setupMock(MockProductService)
+
+			c, rec := newEmployeeContext(http.MethodGet, "/pvz/"+testPvzID+"/products"+tc.query, nil)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := handler.NewProductHandler(MockProductService).List(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockProductService.AssertExpectations(t)
+		})
+	}
+}
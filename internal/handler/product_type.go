@@ -0,0 +1,127 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductTypeHandler struct {
+	service service.ProductTypeService
+}
+
+type ProductTypeCreateRequest struct {
+	Code  string            `json:"code"`
+	Names map[string]string `json:"names"`
+}
+
+type ProductTypeRenameRequest struct {
+	Names map[string]string `json:"names"`
+}
+
+func NewProductTypeHandler(sPTS service.ProductTypeService) *ProductTypeHandler {
+	return &ProductTypeHandler{
+		service: sPTS,
+	}
+}
+
+// List по умолчанию отдаёт только активные типы, ?all=true - все
+func (pth *ProductTypeHandler) List(ctx echo.Context) error {
+	all := false
+	if raw := ctx.QueryParam("all"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "All must be a boolean"})
+		}
+		all = value
+	}
+
+	productTypes, err := pth.service.List(!all)
+	if err != nil {
+		return productTypeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, productTypes)
+}
+
+func (pth *ProductTypeHandler) Create(ctx echo.Context) error {
+	var request ProductTypeCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Code == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Code is required"})
+	}
+
+	productType, err := pth.service.Create(model.ProductType(request.Code), request.Names)
+	if err != nil {
+		return productTypeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, productType)
+}
+
+func (pth *ProductTypeHandler) Rename(ctx echo.Context) error {
+	var request ProductTypeRenameRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	productType, err := pth.service.Rename(productTypeCodeParam(ctx), request.Names)
+	if err != nil {
+		return productTypeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, productType)
+}
+
+func (pth *ProductTypeHandler) Activate(ctx echo.Context) error {
+	return pth.setActive(ctx, true)
+}
+
+func (pth *ProductTypeHandler) Deactivate(ctx echo.Context) error {
+	return pth.setActive(ctx, false)
+}
+
+func (pth *ProductTypeHandler) setActive(ctx echo.Context, active bool) error {
+	productType, err := pth.service.SetActive(productTypeCodeParam(ctx), active)
+	if err != nil {
+		return productTypeError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, productType)
+}
+
+// productTypeCodeParam раскодирует код из пути: коды обычно кириллические
+func productTypeCodeParam(ctx echo.Context) model.ProductType {
+	code := ctx.Param("code")
+	if unescaped, err := url.PathUnescape(code); err == nil {
+		code = unescaped
+	}
+
+	return model.ProductType(code)
+}
+
+func productTypeError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidProductTypeCode):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Code must be 2-32 lowercase letters, digits, '_' or '-'"})
+	case deferr.Is(err, service.ErrInvalidProductTypeNames):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Names must map two-letter locales to non-empty names and include 'ru'"})
+	case deferr.Is(err, service.ErrProductTypeExists):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Product type already exists"})
+	case deferr.Is(err, service.ErrProductTypeNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "Product type not found"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process product type"})
+	}
+}
@@ -0,0 +1,96 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductTypeCreate_TableDriven(t *testing.T) {
+	furniture := map[string]string{"ru": "Мебель", "en": "Furniture"}
+
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockProductTypeService *mocks.MockProductTypeService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_code",
+			requestBody:    map[string]interface{}{"names": furniture},
+			setupMock:      func(MockProductTypeService *mocks.MockProductTypeService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Code is required"},
+		},
+		{
+			name:        "missing_default_name",
+			requestBody: map[string]interface{}{"code": "мебель", "names": map[string]string{"en": "Furniture"}},
+			setupMock: func(MockProductTypeService *mocks.MockProductTypeService) {
+				MockProductTypeService.On("Create", model.ProductType("мебель"), map[string]string{"en": "Furniture"}).
+					Return(nil, service.ErrInvalidProductTypeNames)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Names must map two-letter locales to non-empty names and include 'ru'"},
+		},
+		{
+			name:        "already_exists",
+			requestBody: map[string]interface{}{"code": "обувь", "names": map[string]string{"ru": "Обувь"}},
+			setupMock: func(MockProductTypeService *mocks.MockProductTypeService) {
+				MockProductTypeService.On("Create", model.ProductShoes, map[string]string{"ru": "Обувь"}).
+					Return(nil, service.ErrProductTypeExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Product type already exists"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]interface{}{"code": "мебель", "names": furniture},
+			setupMock: func(MockProductTypeService *mocks.MockProductTypeService) {
+				MockProductTypeService.On("Create", model.ProductType("мебель"), furniture).
+					Return(&model.ProductTypeInfo{Code: "мебель", Names: furniture, Active: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"code": "мебель"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockProductTypeService := new(mocks.MockProductTypeService)
+			tc.setupMock(MockProductTypeService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/product-types", "", tc.requestBody)
+
+			err := handler.NewProductTypeHandler(MockProductTypeService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockProductTypeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductTypeDeactivate_EscapedCode(t *testing.T) {
+	MockProductTypeService := new(mocks.MockProductTypeService)
+	MockProductTypeService.On("SetActive", model.ProductShoes, false).
+		Return(&model.ProductTypeInfo{Code: model.ProductShoes, Active: false}, nil)
+
+	c, rec := newUserAdminContext(http.MethodPost, "/product-types/%D0%BE%D0%B1%D1%83%D0%B2%D1%8C/deactivate", "", nil)
+	c.SetParamNames("code")
+	c.SetParamValues("%D0%BE%D0%B1%D1%83%D0%B2%D1%8C")
+
+	err := handler.NewProductTypeHandler(MockProductTypeService).Deactivate(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	MockProductTypeService.AssertExpectations(t)
+}
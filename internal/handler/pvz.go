@@ -1,21 +1,321 @@
 package handler
 
 import (
-	"log/slog"
+	deferr "errors"
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
 	"github.com/labstack/echo/v4"
 )
 
 type PvzHandler struct {
-	log *slog.Logger
+	service service.PvzService
 }
 
-func NewPvzHandler(log *slog.Logger) *PvzHandler {
+// PvzCreateRequest - тело POST /pvz. Обязателен только city
+type PvzCreateRequest struct {
+	ID               string           `json:"id"`
+	RegistrationDate *time.Time       `json:"registrationDate"`
+	City             string           `json:"city"`
+	Timezone         string           `json:"timezone"`
+	Name             string           `json:"name"`
+	Address          model.PvzAddress `json:"address"`
+	Location         *model.GeoPoint  `json:"location"`
+}
+
+// PvzUpdateRequest - тело PATCH /pvz/:pvzId. Отсутствующие поля не меняются,
+// address заменяется целиком
+type PvzUpdateRequest struct {
+	Name     *string           `json:"name"`
+	Address  *model.PvzAddress `json:"address"`
+	Location *model.GeoPoint   `json:"location"`
+	Timezone *string           `json:"timezone"`
+}
+
+// PvzScheduleRequest - тело PUT /pvz/:pvzId/hours, заменяет график целиком
+type PvzScheduleRequest struct {
+	Weekly     []model.OpeningHours   `json:"weekly"`
+	Exceptions []model.HoursException `json:"exceptions"`
+}
+
+// PvzStatusRequest - тело запросов приостановки и закрытия ПВЗ
+type PvzStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// PvzCapacityRequest - тело PUT /pvz/:pvzId/capacity. total 0 снимает общий лимит,
+// типы, которых нет в byType, остаются без лимита
+type PvzCapacityRequest struct {
+	Total  int                       `json:"total"`
+	ByType map[model.ProductType]int `json:"byType"`
+}
+
+const (
+	radiusMessage   = "Radius must be between 1 and 50000 meters"
+	scheduleMessage = "Weekdays must be unique from 1 to 7, dates unique in YYYY-MM-DD format, " +
+		"times in HH:MM format with opening before closing"
+)
+
+func NewPvzHandler(sPS service.PvzService) *PvzHandler {
 	return &PvzHandler{
-		log: log,
+		service: sPS,
 	}
 }
 
 func (ph *PvzHandler) Create(ctx echo.Context) error {
-	return nil
+	var request PvzCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.City == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "City is required"})
+	}
+
+	pvz := model.PVZ{
+		City:     request.City,
+		Timezone: request.Timezone,
+		Name:     request.Name,
+		Address:  request.Address,
+		Location: request.Location,
+	}
+
+	if request.ID != "" {
+		id, err := parseUUID(request.ID, "Invalid pvz id")
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+		}
+		pvz.ID = id
+	}
+
+	if request.RegistrationDate != nil {
+		pvz.RegistrationDate = *request.RegistrationDate
+	}
+
+	created, err := ph.service.Create(&pvz)
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, created)
+}
+
+func (ph *PvzHandler) List(ctx echo.Context) error {
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	filter := model.PvzFilter{
+		ProductStatus: model.ProductStatus(ctx.QueryParam("productStatus")),
+		Status:        model.PvzStatus(ctx.QueryParam("status")),
+	}
+
+	// Границы дней считаются по местному времени каждого ПВЗ, поэтому принимаются даты без времени
+	if filter.From, err = parseDateParam(ctx, "startDate"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if filter.To, err = parseDateParam(ctx, "endDate"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	list, err := ph.service.List(filter, page, limit)
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, list)
+}
+
+func (ph *PvzHandler) SetCapacity(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request PvzCapacityRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	pvz, err := ph.service.SetCapacity(pvzID, model.PvzCapacity{Total: request.Total, ByType: request.ByType})
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, pvz)
+}
+
+// Nearby - GET /pvz/nearby?lat=&lon=&radius=. radius в метрах, по умолчанию 5 км
+func (ph *PvzHandler) Nearby(ctx echo.Context) error {
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	lat, latErr := strconv.ParseFloat(ctx.QueryParam("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(ctx.QueryParam("lon"), 64)
+	if latErr != nil || lonErr != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "lat and lon are required numbers"})
+	}
+
+	radius := service.DefaultNearbyRadius
+	if value := ctx.QueryParam("radius"); value != "" {
+		if radius, err = strconv.Atoi(value); err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: radiusMessage})
+		}
+	}
+
+	list, err := ph.service.Nearby(model.GeoPoint{Lat: lat, Lon: lon}, radius, page, limit)
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, list)
+}
+
+func (ph *PvzHandler) Update(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request PvzUpdateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	pvz, err := ph.service.Update(pvzID, model.PvzUpdate{
+		Name:     request.Name,
+		Address:  request.Address,
+		Location: request.Location,
+		Timezone: request.Timezone,
+	})
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, pvz)
+}
+
+func (ph *PvzHandler) Schedule(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	schedule, err := ph.service.Schedule(pvzID)
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, schedule)
+}
+
+func (ph *PvzHandler) SetSchedule(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request PvzScheduleRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	schedule, err := ph.service.SetSchedule(model.PvzSchedule{PvzID: pvzID, Weekly: request.Weekly, Exceptions: request.Exceptions})
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, schedule)
+}
+
+func (ph *PvzHandler) Suspend(ctx echo.Context) error {
+	return ph.changeStatus(ctx, ph.service.Suspend)
+}
+
+func (ph *PvzHandler) Activate(ctx echo.Context) error {
+	return ph.changeStatus(ctx, func(actorID, pvzID, _ string) (*model.PVZ, error) {
+		return ph.service.Activate(actorID, pvzID)
+	})
+}
+
+func (ph *PvzHandler) Close(ctx echo.Context) error {
+	return ph.changeStatus(ctx, ph.service.Close)
+}
+
+// changeStatus разбирает id ПВЗ и необязательную причину и вызывает переход статуса
+func (ph *PvzHandler) changeStatus(ctx echo.Context, change func(actorID, pvzID, reason string) (*model.PVZ, error)) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request PvzStatusRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	pvz, err := change(actorID(ctx), pvzID, request.Reason)
+	if err != nil {
+		return pvzError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, pvz)
+}
+
+func pvzError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidDateRange):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "startDate must not be after endDate"})
+	case deferr.Is(err, service.ErrUnknownProductStatus):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: productStatusMessage})
+	case deferr.Is(err, service.ErrCityNotAllowed):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ cannot be opened in this city"})
+	case deferr.Is(err, service.ErrPvzExists):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ with this id already exists"})
+	case deferr.Is(err, service.ErrPvzNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "PVZ not found"})
+	case deferr.Is(err, service.ErrUnknownPvzStatus):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Status must be one of 'active', 'suspended', 'closed'"})
+	case deferr.Is(err, service.ErrPvzFieldTooLong):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Name, street and house must be at most 200 characters"})
+	case deferr.Is(err, service.ErrInvalidPostal):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Postal code must be 6 digits"})
+	case deferr.Is(err, service.ErrInvalidLocation):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Latitude must be between -90 and 90, longitude between -180 and 180"})
+	case deferr.Is(err, service.ErrInvalidSchedule):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: scheduleMessage})
+	case deferr.Is(err, service.ErrInvalidRadius):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: radiusMessage})
+	case deferr.Is(err, service.ErrReasonTooLong):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reason must be at most 500 characters"})
+	case deferr.Is(err, service.ErrPvzClosed):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ is closed"})
+	case deferr.Is(err, service.ErrPvzStatusConflict):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ status does not allow this change"})
+	case deferr.Is(err, service.ErrPvzHasOpenReception):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ has an open reception"})
+	case deferr.Is(err, service.ErrPvzNotEmpty):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ still has products that are not issued or shipped back"})
+	case deferr.Is(err, service.ErrInvalidCapacity):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Capacity must be between 1 and 100000, total 0 means no limit"})
+	case deferr.Is(err, service.ErrUnknownProductType):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown product type"})
+	case deferr.Is(err, service.ErrInvalidTimezone):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Timezone must be an IANA time zone name, e.g. Europe/Moscow"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process PVZ"})
+	}
 }
@@ -0,0 +1,492 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPvzCreate_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_city",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "City is required"},
+		},
+		{
+			name:           "invalid_id",
+			requestBody:    map[string]string{"id": "42", "city": "Москва"},
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:        "city_not_allowed",
+			requestBody: map[string]string{"city": "Новосибирск"},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Create", &model.PVZ{City: "Новосибирск"}).Return(nil, service.ErrCityNotAllowed)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ cannot be opened in this city"},
+		},
+		{
+			name:        "duplicate_id",
+			requestBody: map[string]string{"id": testPvzID, "city": "Москва"},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Create", &model.PVZ{ID: testPvzID, City: "Москва"}).Return(nil, service.ErrPvzExists)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ with this id already exists"},
+		},
+		{
+			name:        "invalid_timezone",
+			requestBody: map[string]string{"city": "Казань", "timezone": "Mars/Olympus"},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Create", &model.PVZ{City: "Казань", Timezone: "Mars/Olympus"}).Return(nil, service.ErrInvalidTimezone)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Timezone must be an IANA time zone name, e.g. Europe/Moscow"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]string{"city": "Казань"},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Create", &model.PVZ{City: "Казань"}).Return(&model.PVZ{ID: testPvzID, City: "Казань", Timezone: model.DefaultTimezone}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"id": testPvzID, "city": "Казань", "timezone": "Europe/Moscow"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/pvz", "", tc.requestBody)
+
+			err := handler.NewPvzHandler(MockPvzService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzList_TableDriven(t *testing.T) {
+	startDate := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	moscowAfterMidnight := time.Date(2025, 3, 31, 21, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+		expectedParts  []string
+	}{
+		{
+			name:           "invalid_start_date",
+			query:          "?startDate=yesterday",
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "startDate must be a date in YYYY-MM-DD format"},
+		},
+		{
+			name:           "start_date_with_time",
+			query:          "?startDate=2025-04-01T00:00:00Z",
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "startDate must be a date in YYYY-MM-DD format"},
+		},
+		{
+			name:  "unknown_product_status",
+			query: "?productStatus=lost",
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("List", model.PvzFilter{ProductStatus: "lost"}, 1, service.DefaultPageLimit).
+					Return(nil, service.ErrUnknownProductStatus)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Status must be one of 'received', 'ready', 'issued', 'returned'"},
+		},
+		{
+			name:  "filtered_list",
+			query: "?startDate=2025-04-01&productStatus=ready&limit=5",
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("List", model.PvzFilter{From: &startDate, ProductStatus: model.ProductReady}, 1, 5).
+					Return([]model.PvzListItem{{Pvz: model.PVZ{ID: testPvzID, City: "Казань"}}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			// Дата передаётся сервису без сдвига: 2025-04-01 в Москве начинается 2025-03-31 в 21:00 UTC,
+			// и такая приёмка попадает в день 2025-04-01
+			name:  "local_day_of_non_utc_pvz",
+			query: "?startDate=2025-04-01&endDate=2025-04-01",
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("List", model.PvzFilter{From: &startDate, To: &startDate}, 1, service.DefaultPageLimit).
+					Return([]model.PvzListItem{{
+						Pvz: model.PVZ{ID: testPvzID, City: "Москва", Timezone: "Europe/Moscow"},
+						Receptions: []model.ReceptionWithProducts{{
+							Reception: model.Reception{ID: testReceptionID, PvzID: testPvzID, DateTime: moscowAfterMidnight},
+						}},
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedParts:  []string{`"timezone":"Europe/Moscow"`, `"dateTime":"2025-03-31T21:30:00Z"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newEmployeeContext(http.MethodGet, "/pvz"+tc.query, nil)
+
+			err := handler.NewPvzHandler(MockPvzService).List(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+			for _, part := range tc.expectedParts {
+				assert.Contains(t, rec.Body.String(), part)
+			}
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzSetCapacity_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pvzID          string
+		requestBody    interface{}
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "invalid_pvz_id",
+			pvzID:          "42",
+			requestBody:    map[string]int{"total": 100},
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:        "negative_capacity",
+			pvzID:       testPvzID,
+			requestBody: map[string]int{"total": -1},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("SetCapacity", testPvzID, model.PvzCapacity{Total: -1}).Return(nil, service.ErrInvalidCapacity)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Capacity must be between 1 and 100000, total 0 means no limit"},
+		},
+		{
+			name:        "pvz_not_found",
+			pvzID:       testPvzID,
+			requestBody: map[string]int{"total": 100},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("SetCapacity", testPvzID, model.PvzCapacity{Total: 100}).Return(nil, service.ErrPvzNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "PVZ not found"},
+		},
+		{
+			name:        "successful_update",
+			pvzID:       testPvzID,
+			requestBody: map[string]interface{}{"total": 100, "byType": map[string]int{"обувь": 20}},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				capacity := model.PvzCapacity{Total: 100, ByType: map[model.ProductType]int{model.ProductShoes: 20}}
+				MockPvzService.On("SetCapacity", testPvzID, capacity).Return(&model.PVZ{
+					ID:          testPvzID,
+					City:        "Казань",
+					Capacity:    &capacity,
+					Utilization: &model.PvzUtilization{Total: 12, ByType: map[model.ProductType]int{model.ProductShoes: 12}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newUserAdminContext(http.MethodPut, "/pvz/"+tc.pvzID+"/capacity", "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(tc.pvzID)
+
+			err := handler.NewPvzHandler(MockPvzService).SetCapacity(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzUpdate_TableDriven(t *testing.T) {
+	address := model.PvzAddress{PostalCode: "420111", Street: "ул. Баумана", House: "1"}
+
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:        "pvz_closed",
+			requestBody: map[string]interface{}{"address": address},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Update", testPvzID, model.PvzUpdate{Address: &address}).Return(nil, service.ErrPvzClosed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ is closed"},
+		},
+		{
+			name:        "invalid_location",
+			requestBody: map[string]interface{}{"location": map[string]float64{"lat": 91, "lon": 49}},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Update", testPvzID, model.PvzUpdate{Location: &model.GeoPoint{Lat: 91, Lon: 49}}).
+					Return(nil, service.ErrInvalidLocation)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Latitude must be between -90 and 90, longitude between -180 and 180"},
+		},
+		{
+			name:        "successful_update",
+			requestBody: map[string]interface{}{"address": address},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Update", testPvzID, model.PvzUpdate{Address: &address}).
+					Return(&model.PVZ{ID: testPvzID, City: "Казань", Address: address, Status: model.PvzActive}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "active"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newUserAdminContext(http.MethodPatch, "/pvz/"+testPvzID, "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := handler.NewPvzHandler(MockPvzService).Update(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzStatus_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		action         func(h *handler.PvzHandler) func(echo.Context) error
+		requestBody    interface{}
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:        "suspend",
+			action:      func(h *handler.PvzHandler) func(echo.Context) error { return h.Suspend },
+			requestBody: map[string]string{"reason": "Ремонт"},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Suspend", testModeratorID, testPvzID, "Ремонт").
+					Return(&model.PVZ{ID: testPvzID, Status: model.PvzSuspended, StatusReason: "Ремонт"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "suspended", "statusReason": "Ремонт"},
+		},
+		{
+			name:   "activate_closed",
+			action: func(h *handler.PvzHandler) func(echo.Context) error { return h.Activate },
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Activate", testModeratorID, testPvzID).Return(nil, service.ErrPvzStatusConflict)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ status does not allow this change"},
+		},
+		{
+			name:   "close_with_open_reception",
+			action: func(h *handler.PvzHandler) func(echo.Context) error { return h.Close },
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Close", testModeratorID, testPvzID, "").Return(nil, service.ErrPvzHasOpenReception)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ has an open reception"},
+		},
+		{
+			name:   "close_not_empty",
+			action: func(h *handler.PvzHandler) func(echo.Context) error { return h.Close },
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Close", testModeratorID, testPvzID, "").Return(nil, service.ErrPvzNotEmpty)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ still has products that are not issued or shipped back"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/pvz/"+testPvzID+"/status", "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := tc.action(handler.NewPvzHandler(MockPvzService))(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzNearby_TableDriven(t *testing.T) {
+	center := model.GeoPoint{Lat: 55.7963, Lon: 49.1088}
+
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_lon",
+			query:          "?lat=55.7963",
+			setupMock:      func(MockPvzService *mocks.MockPvzService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "lat and lon are required numbers"},
+		},
+		{
+			name:  "radius_too_large",
+			query: "?lat=55.7963&lon=49.1088&radius=100000",
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Nearby", center, 100000, 1, service.DefaultPageLimit).Return(nil, service.ErrInvalidRadius)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Radius must be between 1 and 50000 meters"},
+		},
+		{
+			name:  "default_radius",
+			query: "?lat=55.7963&lon=49.1088",
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("Nearby", center, service.DefaultNearbyRadius, 1, service.DefaultPageLimit).
+					Return([]model.NearbyPvz{{PVZ: model.PVZ{ID: testPvzID, City: "Казань", Location: &center}, Distance: 0}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newEmployeeContext(http.MethodGet, "/pvz/nearby"+tc.query, nil)
+
+			err := handler.NewPvzHandler(MockPvzService).Nearby(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzSetSchedule_TableDriven(t *testing.T) {
+	weekly := []model.OpeningHours{{Weekday: 1, Opens: "09:00", Closes: "21:00"}}
+	exceptions := []model.HoursException{{Date: "2025-05-09", Note: "День Победы"}}
+
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockPvzService *mocks.MockPvzService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:        "closes_before_opens",
+			requestBody: map[string]interface{}{"weekly": []model.OpeningHours{{Weekday: 1, Opens: "21:00", Closes: "09:00"}}},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				MockPvzService.On("SetSchedule", model.PvzSchedule{
+					PvzID:  testPvzID,
+					Weekly: []model.OpeningHours{{Weekday: 1, Opens: "21:00", Closes: "09:00"}},
+				}).Return(nil, service.ErrInvalidSchedule)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]string{"message": "Weekdays must be unique from 1 to 7, dates unique in YYYY-MM-DD format, " +
+				"times in HH:MM format with opening before closing"},
+		},
+		{
+			name:        "successful_update",
+			requestBody: map[string]interface{}{"weekly": weekly, "exceptions": exceptions},
+			setupMock: func(MockPvzService *mocks.MockPvzService) {
+				schedule := model.PvzSchedule{PvzID: testPvzID, Weekly: weekly, Exceptions: exceptions}
+				MockPvzService.On("SetSchedule", schedule).Return(&schedule, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"pvzId": testPvzID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPvzService := new(mocks.MockPvzService)
+			tc.setupMock(MockPvzService)
+
+			c, rec := newUserAdminContext(http.MethodPut, "/pvz/"+testPvzID+"/hours", "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := handler.NewPvzHandler(MockPvzService).SetSchedule(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPvzService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ReceptionHandler struct {
+	service service.ReceptionService
+}
+
+type ReceptionCreateRequest struct {
+	PvzID string `json:"pvzId"`
+}
+
+// ProductCreateRequest - тело POST /products. Обязательны только type и pvzId
+type ProductCreateRequest struct {
+	Type     string `json:"type"`
+	PvzID    string `json:"pvzId"`
+	Barcode  string `json:"barcode"`
+	CellCode string `json:"cellCode"`
+
+	WeightGrams   *int                     `json:"weightGrams"`
+	Dimensions    *model.ProductDimensions `json:"dimensions"`
+	DeclaredValue *int64                   `json:"declaredValue"`
+	Condition     string                   `json:"condition"`
+	Note          string                   `json:"note"`
+}
+
+// ReceptionReopenRequest - тело POST /pvz/:pvzId/reopen_last_reception. reason обязателен
+type ReceptionReopenRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ReceptionResponse struct {
+	ID       string     `json:"id"`
+	DateTime time.Time  `json:"dateTime"`
+	PvzID    string     `json:"pvzId"`
+	Status   string     `json:"status"`
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+}
+
+type ProductResponse struct {
+	ID          string    `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	Type        string    `json:"type"`
+	ReceptionID string    `json:"receptionId"`
+	Barcode     string    `json:"barcode,omitempty"`
+	Status      string    `json:"status"`
+	CellID      string    `json:"cellId,omitempty"`
+
+	WeightGrams   *int                     `json:"weightGrams,omitempty"`
+	Dimensions    *model.ProductDimensions `json:"dimensions,omitempty"`
+	DeclaredValue *int64                   `json:"declaredValue,omitempty"`
+	Condition     string                   `json:"condition,omitempty"`
+	Note          string                   `json:"note,omitempty"`
+}
+
+func NewReceptionHandler(sRS service.ReceptionService) *ReceptionHandler {
+	return &ReceptionHandler{
+		service: sRS,
+	}
+}
+
+func newReceptionResponse(reception *model.Reception) ReceptionResponse {
+	return ReceptionResponse{
+		ID:       reception.ID,
+		DateTime: reception.DateTime,
+		PvzID:    reception.PvzID,
+		Status:   string(reception.Status),
+		ClosedAt: reception.ClosedAt,
+	}
+}
+
+func newProductResponse(product *model.Product) ProductResponse {
+	return ProductResponse{
+		ID:          product.ID,
+		DateTime:    product.DateTime,
+		Type:        string(product.Type),
+		ReceptionID: product.ReceptionID,
+		Barcode:     product.Barcode,
+		Status:      string(product.Status),
+		CellID:      product.CellID,
+
+		WeightGrams:   product.WeightGrams,
+		Dimensions:    product.Dimensions,
+		DeclaredValue: product.DeclaredValue,
+		Condition:     string(product.Condition),
+		Note:          product.Note,
+	}
+}
+
+func (rh *ReceptionHandler) Create(ctx echo.Context) error {
+	var request ReceptionCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.PvzID == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ id is required"})
+	}
+
+	pvzID, err := parseUUID(request.PvzID, "Invalid pvz id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	reception, err := rh.service.OpenReception(actorID(ctx), actorRole(ctx), pvzID)
+	if err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, newReceptionResponse(reception))
+}
+
+func (rh *ReceptionHandler) AddProduct(ctx echo.Context) error {
+	var request ProductCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Type == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Type is required"})
+	}
+
+	if request.PvzID == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ id is required"})
+	}
+
+	pvzID, err := parseUUID(request.PvzID, "Invalid pvz id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	product, err := rh.service.AddProduct(actorID(ctx), pvzID, &model.Product{
+		Type:          model.ProductType(request.Type),
+		Barcode:       request.Barcode,
+		WeightGrams:   request.WeightGrams,
+		Dimensions:    request.Dimensions,
+		DeclaredValue: request.DeclaredValue,
+		Condition:     model.ProductCondition(request.Condition),
+		Note:          request.Note,
+	}, request.CellCode)
+	if err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, newProductResponse(product))
+}
+
+func (rh *ReceptionHandler) DeleteLastProduct(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if _, err := rh.service.DeleteLastProduct(actorID(ctx), pvzID); err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (rh *ReceptionHandler) CloseLastReception(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	reception, err := rh.service.CloseLastReception(actorID(ctx), pvzID)
+	if err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, newReceptionResponse(reception))
+}
+
+func (rh *ReceptionHandler) ReopenLastReception(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request ReceptionReopenRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	reception, err := rh.service.ReopenLastReception(actorID(ctx), pvzID, request.Reason)
+	if err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, newReceptionResponse(reception))
+}
+
+func (rh *ReceptionHandler) Events(ctx echo.Context) error {
+	receptionID, err := parseUUID(ctx.Param("receptionId"), "Invalid reception id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	events, err := rh.service.Events(receptionID)
+	if err != nil {
+		return receptionError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, events)
+}
+
+func receptionError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrPvzNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "PVZ not found"})
+	case deferr.Is(err, service.ErrNotAssigned):
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Employee is not assigned to this PVZ"})
+	case deferr.Is(err, service.ErrPvzNotActive):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ is suspended or closed"})
+	case deferr.Is(err, service.ErrOutsideHours):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Reception cannot be opened outside PVZ working hours"})
+	case deferr.Is(err, service.ErrReceptionInProgress):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ already has an open reception"})
+	case deferr.Is(err, service.ErrNoOpenReception):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ has no open reception"})
+	case deferr.Is(err, service.ErrUnknownProductType):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown or inactive product type"})
+	case deferr.Is(err, service.ErrInvalidBarcode):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: barcodeFormatMessage})
+	case deferr.Is(err, service.ErrBarcodeDuplicate):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Product with this barcode is already accepted"})
+	case deferr.Is(err, service.ErrInvalidWeight):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Weight must be between 1 and 1000000 grams"})
+	case deferr.Is(err, service.ErrInvalidDimensions):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Each dimension must be between 1 and 10000 mm"})
+	case deferr.Is(err, service.ErrInvalidDeclaredValue):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Declared value must be between 0 and 10000000000 kopecks"})
+	case deferr.Is(err, service.ErrUnknownCondition):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Condition must be one of 'ok', 'damaged', 'wet', 'opened'"})
+	case deferr.Is(err, service.ErrNoteTooLong):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Note must be at most 500 characters"})
+	case deferr.Is(err, service.ErrCellNotFound):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Storage cell not found on this PVZ"})
+	case deferr.Is(err, service.ErrCellFull):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Storage cell is full"})
+	case deferr.Is(err, service.ErrPvzFull):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ is full"})
+	case deferr.Is(err, service.ErrPvzTypeFull):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ has no room for products of this type"})
+	case deferr.Is(err, service.ErrPvzClosed):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ is closed"})
+	case deferr.Is(err, service.ErrNoClosedReception):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ has no closed reception to reopen"})
+	case deferr.Is(err, service.ErrReopenDisabled):
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Reception reopen is disabled"})
+	case deferr.Is(err, service.ErrReopenExpired):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Reception reopen window has expired"})
+	case deferr.Is(err, service.ErrReceptionProcessed):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Reception products are already being issued or returned"})
+	case deferr.Is(err, service.ErrReopenConflict):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Reception changed while reopening, try again"})
+	case deferr.Is(err, service.ErrReasonRequired):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reason is required"})
+	case deferr.Is(err, service.ErrReasonTooLong):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reason must be at most 500 characters"})
+	case deferr.Is(err, service.ErrNoProducts):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Open reception has no products"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process reception"})
+	}
+}
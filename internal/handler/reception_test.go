@@ -0,0 +1,486 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPvzID       = "0b6a8f0e-3c1d-4e2f-9a8b-7c6d5e4f3a2b"
+	testReceptionID = "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"
+)
+
+type ReceptionTestCase struct {
+	name           string
+	pvzID          string
+	requestBody    interface{}
+	setupMock      func(MockReceptionService *mocks.MockReceptionService)
+	expectedStatus int
+	expectedBody   interface{}
+}
+
+func newEmployeeContext(method, target string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	var reqBody []byte
+	if bodyStr, ok := body.(string); ok {
+		reqBody = []byte(bodyStr)
+	} else if body != nil {
+		reqBody, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	c := e.NewContext(req, rec)
+	c.Set(middleware.ContextUserID, testUserID)
+	c.Set(middleware.ContextRole, model.RoleEmployee)
+
+	return c, rec
+}
+
+func TestReceptionCreate_TableDriven(t *testing.T) {
+	testCases := []ReceptionTestCase{
+		{
+			name:           "missing_pvz_id",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockReceptionService *mocks.MockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ id is required"},
+		},
+		{
+			name:           "invalid_pvz_id",
+			requestBody:    map[string]string{"pvzId": "123"},
+			setupMock:      func(MockReceptionService *mocks.MockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:        "not_assigned",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("OpenReception", testUserID, model.RoleEmployee, testPvzID).
+					Return(nil, service.ErrNotAssigned)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Employee is not assigned to this PVZ"},
+		},
+		{
+			name:        "pvz_suspended",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("OpenReception", testUserID, model.RoleEmployee, testPvzID).
+					Return(nil, service.ErrPvzNotActive)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ is suspended or closed"},
+		},
+		{
+			name:        "outside_hours",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("OpenReception", testUserID, model.RoleEmployee, testPvzID).
+					Return(nil, service.ErrOutsideHours)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Reception cannot be opened outside PVZ working hours"},
+		},
+		{
+			name:        "reception_in_progress",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("OpenReception", testUserID, model.RoleEmployee, testPvzID).
+					Return(nil, service.ErrReceptionInProgress)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ already has an open reception"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("OpenReception", testUserID, model.RoleEmployee, testPvzID).
+					Return(&model.Reception{ID: testReceptionID, DateTime: time.Now(), PvzID: testPvzID, Status: model.ReceptionInProgress}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"id": testReceptionID, "pvzId": testPvzID, "status": "in_progress"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReceptionService := new(mocks.MockReceptionService)
+			tc.setupMock(MockReceptionService)
+
+			handler := handler.NewReceptionHandler(MockReceptionService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/receptions", tc.requestBody)
+
+			err := handler.Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReceptionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddProduct_TableDriven(t *testing.T) {
+	testCases := []ReceptionTestCase{
+		{
+			name:        "invalid_type",
+			requestBody: map[string]string{"type": "мебель", "pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: "мебель"}, "").
+					Return(nil, service.ErrUnknownProductType)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Unknown or inactive product type"},
+		},
+		{
+			name:        "no_open_reception",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes}, "").
+					Return(nil, service.ErrNoOpenReception)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ has no open reception"},
+		},
+		{
+			name:        "invalid_barcode",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID, "barcode": "-12"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes, Barcode: "-12"}, "").
+					Return(nil, service.ErrInvalidBarcode)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Barcode must be 6-32 latin letters or digits, hyphens allowed inside"},
+		},
+		{
+			name:        "duplicate_barcode",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID, "barcode": testBarcode},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes, Barcode: testBarcode}, "").
+					Return(nil, service.ErrBarcodeDuplicate)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Product with this barcode is already accepted"},
+		},
+		{
+			name:        "cell_full",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID, "cellCode": "A-1"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes}, "A-1").
+					Return(nil, service.ErrCellFull)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Storage cell is full"},
+		},
+		{
+			name:        "pvz_full",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes}, "").
+					Return(nil, service.ErrPvzFull)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ is full"},
+		},
+		{
+			name:        "unknown_condition",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID, "condition": "broken"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes, Condition: "broken"}, "").
+					Return(nil, service.ErrUnknownCondition)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Condition must be one of 'ok', 'damaged', 'wet', 'opened'"},
+		},
+		{
+			name: "invalid_dimensions",
+			requestBody: map[string]interface{}{
+				"type": "обувь", "pvzId": testPvzID,
+				"dimensions": map[string]int{"lengthMm": 300, "widthMm": 0, "heightMm": 100},
+			},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{
+					Type:       model.ProductShoes,
+					Dimensions: &model.ProductDimensions{LengthMm: 300, HeightMm: 100},
+				}, "").
+					Return(nil, service.ErrInvalidDimensions)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Each dimension must be between 1 and 10000 mm"},
+		},
+		{
+			name:        "successful_add_with_details",
+			requestBody: map[string]interface{}{"type": "обувь", "pvzId": testPvzID, "weightGrams": 1200, "condition": "damaged", "note": "мятая коробка"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				weight := 1200
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{
+					Type:        model.ProductShoes,
+					WeightGrams: &weight,
+					Condition:   model.ConditionDamaged,
+					Note:        "мятая коробка",
+				}, "").
+					Return(&model.Product{
+						ID: testUserID, DateTime: time.Now(), Type: model.ProductShoes, ReceptionID: testReceptionID,
+						WeightGrams: &weight, Condition: model.ConditionDamaged, Note: "мятая коробка",
+					}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"condition": "damaged", "note": "мятая коробка"},
+		},
+		{
+			name:        "successful_add",
+			requestBody: map[string]string{"type": "обувь", "pvzId": testPvzID},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("AddProduct", testUserID, testPvzID, &model.Product{Type: model.ProductShoes}, "").
+					Return(&model.Product{ID: testUserID, DateTime: time.Now(), Type: model.ProductShoes, ReceptionID: testReceptionID}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"type": "обувь", "receptionId": testReceptionID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReceptionService := new(mocks.MockReceptionService)
+			tc.setupMock(MockReceptionService)
+
+			handler := handler.NewReceptionHandler(MockReceptionService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/products", tc.requestBody)
+
+			err := handler.AddProduct(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReceptionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteLastProduct_TableDriven(t *testing.T) {
+	testCases := []ReceptionTestCase{
+		{
+			name:           "invalid_pvz_id",
+			pvzID:          "123",
+			setupMock:      func(MockReceptionService *mocks.MockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:  "no_products",
+			pvzID: testPvzID,
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("DeleteLastProduct", testUserID, testPvzID).
+					Return(nil, service.ErrNoProducts)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Open reception has no products"},
+		},
+		{
+			name:  "product_placed_in_cell",
+			pvzID: testPvzID,
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("DeleteLastProduct", testUserID, testPvzID).
+					Return(&model.Product{ID: testUserID, Type: model.ProductShoes, ReceptionID: testReceptionID, CellID: testCellID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReceptionService := new(mocks.MockReceptionService)
+			tc.setupMock(MockReceptionService)
+
+			handler := handler.NewReceptionHandler(MockReceptionService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/pvz/"+tc.pvzID+"/delete_last_product", nil)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(tc.pvzID)
+
+			err := handler.DeleteLastProduct(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReceptionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCloseLastReception_TableDriven(t *testing.T) {
+	testCases := []ReceptionTestCase{
+		{
+			name:           "invalid_pvz_id",
+			pvzID:          "123",
+			setupMock:      func(MockReceptionService *mocks.MockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:  "pvz_not_found",
+			pvzID: testPvzID,
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("CloseLastReception", testUserID, testPvzID).
+					Return(nil, service.ErrPvzNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "PVZ not found"},
+		},
+		{
+			name:  "successful_close",
+			pvzID: testPvzID,
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("CloseLastReception", testUserID, testPvzID).
+					Return(&model.Reception{ID: testReceptionID, DateTime: time.Now(), PvzID: testPvzID, Status: model.ReceptionClose}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "close"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReceptionService := new(mocks.MockReceptionService)
+			tc.setupMock(MockReceptionService)
+
+			handler := handler.NewReceptionHandler(MockReceptionService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/pvz/"+tc.pvzID+"/close_last_reception", nil)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(tc.pvzID)
+
+			err := handler.CloseLastReception(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReceptionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReopenLastReception_TableDriven(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+
+	testCases := []ReceptionTestCase{
+		{
+			name:           "invalid_pvz_id",
+			pvzID:          "123",
+			setupMock:      func(MockReceptionService *mocks.MockReceptionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:        "missing_reason",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "").
+					Return(nil, service.ErrReasonRequired)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Reason is required"},
+		},
+		{
+			name:        "window_expired",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{"reason": "забыли товар"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "забыли товар").
+					Return(nil, service.ErrReopenExpired)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Reception reopen window has expired"},
+		},
+		{
+			name:        "reception_in_progress",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{"reason": "забыли товар"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "забыли товар").
+					Return(nil, service.ErrReceptionInProgress)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ already has an open reception"},
+		},
+		{
+			name:        "no_closed_reception",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{"reason": "забыли товар"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "забыли товар").
+					Return(nil, service.ErrNoClosedReception)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ has no closed reception to reopen"},
+		},
+		{
+			name:        "products_processed",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{"reason": "забыли товар"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "забыли товар").
+					Return(nil, service.ErrReceptionProcessed)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Reception products are already being issued or returned"},
+		},
+		{
+			name:        "successful_reopen",
+			pvzID:       testPvzID,
+			requestBody: map[string]string{"reason": "забыли товар"},
+			setupMock: func(MockReceptionService *mocks.MockReceptionService) {
+				MockReceptionService.On("ReopenLastReception", testModeratorID, testPvzID, "забыли товар").
+					Return(&model.Reception{ID: testReceptionID, DateTime: createdAt, PvzID: testPvzID, Status: model.ReceptionInProgress}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"status": "in_progress"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReceptionService := new(mocks.MockReceptionService)
+			tc.setupMock(MockReceptionService)
+
+			handler := handler.NewReceptionHandler(MockReceptionService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/pvz/"+tc.pvzID+"/reopen_last_reception", "", tc.requestBody)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(tc.pvzID)
+
+			err := handler.ReopenLastReception(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReceptionService.AssertExpectations(t)
+		})
+	}
+}
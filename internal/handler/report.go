@@ -0,0 +1,53 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportHandler struct {
+	service service.ReportService
+}
+
+func NewReportHandler(sRS service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		service: sRS,
+	}
+}
+
+func (rh *ReportHandler) DailyReceptions(ctx echo.Context) error {
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	filter := model.ReportFilter{City: ctx.QueryParam("city")}
+
+	if raw := ctx.QueryParam("pvzId"); raw != "" {
+		if filter.PvzID, err = parseUUID(raw, "Invalid pvz id"); err != nil {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+		}
+	}
+
+	if filter.From, err = parseDateParam(ctx, "from"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if filter.To, err = parseDateParam(ctx, "to"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	stats, err := rh.service.DailyReceptions(filter, page, limit)
+	if deferr.Is(err, service.ErrInvalidDateRange) {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "from must not be after to"})
+	} else if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to build report"})
+	}
+
+	return ctx.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyReceptionsReport_TableDriven(t *testing.T) {
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 4, 30, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		setupMock      func(MockReportService *mocks.MockReportService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "invalid_pvz_id",
+			query:          "?pvzId=42",
+			setupMock:      func(MockReportService *mocks.MockReportService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid pvz id"},
+		},
+		{
+			name:           "invalid_date",
+			query:          "?from=01.04.2025",
+			setupMock:      func(MockReportService *mocks.MockReportService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "from must be a date in YYYY-MM-DD format"},
+		},
+		{
+			name:  "reversed_range",
+			query: "?from=2025-04-30&to=2025-04-01",
+			setupMock: func(MockReportService *mocks.MockReportService) {
+				MockReportService.On("DailyReceptions", model.ReportFilter{From: &to, To: &from}, 1, 10).
+					Return(nil, service.ErrInvalidDateRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "from must not be after to"},
+		},
+		{
+			name:  "filtered_report",
+			query: "?pvzId=" + testPvzID + "&city=Казань&from=2025-04-01&to=2025-04-30",
+			setupMock: func(MockReportService *mocks.MockReportService) {
+				MockReportService.On("DailyReceptions", model.ReportFilter{PvzID: testPvzID, City: "Казань", From: &from, To: &to}, 1, 10).
+					Return([]model.DailyReceptionStats{{
+						PvzID:      testPvzID,
+						City:       "Казань",
+						Date:       "2025-04-02",
+						Receptions: 2,
+						Products:   3,
+						ByType:     map[model.ProductType]int{model.ProductShoes: 2, model.ProductClothes: 1},
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReportService := new(mocks.MockReportService)
+			tc.setupMock(MockReportService)
+
+			c, rec := newUserAdminContext(http.MethodGet, "/reports/receptions/daily"+tc.query, "", nil)
+
+			err := handler.NewReportHandler(MockReportService).DailyReceptions(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReportService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ReturnHandler struct {
+	service service.ReturnService
+}
+
+type ReturnBatchCreateRequest struct {
+	PvzID string `json:"pvzId"`
+}
+
+type ProductReturnRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+func NewReturnHandler(sRS service.ReturnService) *ReturnHandler {
+	return &ReturnHandler{
+		service: sRS,
+	}
+}
+
+func (rh *ReturnHandler) OpenBatch(ctx echo.Context) error {
+	var request ReturnBatchCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.PvzID == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ id is required"})
+	}
+
+	pvzID, err := parseUUID(request.PvzID, "Invalid pvz id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	batch, err := rh.service.OpenBatch(actorID(ctx), pvzID)
+	if err != nil {
+		return returnError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, batch)
+}
+
+func (rh *ReturnHandler) CloseLastBatch(ctx echo.Context) error {
+	pvzID, err := pvzIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	batch, err := rh.service.CloseLastBatch(actorID(ctx), pvzID)
+	if err != nil {
+		return returnError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, batch)
+}
+
+func (rh *ReturnHandler) Return(ctx echo.Context) error {
+	productID, err := productIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request ProductReturnRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Reason == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reason is required"})
+	}
+
+	productReturn, err := rh.service.Return(actorID(ctx), productID, model.ReturnReason(request.Reason), request.Comment)
+	if err != nil {
+		return returnError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, productReturn)
+}
+
+func returnError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrUnknownReturnReason):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Reason must be one of 'refused', 'not_collected', 'damaged', 'wrong_item', 'customer_return'"})
+	case deferr.Is(err, service.ErrReturnCommentTooLong):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Comment must be at most 500 characters"})
+	case deferr.Is(err, service.ErrReturnBatchInProgress):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ already has an open return batch"})
+	case deferr.Is(err, service.ErrNoOpenReturnBatch):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "PVZ has no open return batch"})
+	case deferr.Is(err, service.ErrReturnBatchEmpty):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Return batch has no returns"})
+	case deferr.Is(err, service.ErrPvzNotActive):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "PVZ is suspended or closed"})
+	default:
+		return productError(ctx, err)
+	}
+}
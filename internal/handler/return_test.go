@@ -0,0 +1,208 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const testReturnBatchID = "2c1b0a9f-8e7d-4c6b-9a59-4837261504f3"
+
+func TestReturnBatchOpen_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockReturnService *mocks.MockReturnService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_pvz_id",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockReturnService *mocks.MockReturnService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ id is required"},
+		},
+		{
+			name:        "batch_in_progress",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("OpenBatch", testUserID, testPvzID).Return(nil, service.ErrReturnBatchInProgress)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ already has an open return batch"},
+		},
+		{
+			name:        "pvz_not_active",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("OpenBatch", testUserID, testPvzID).Return(nil, service.ErrPvzNotActive)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "PVZ is suspended or closed"},
+		},
+		{
+			name:        "successful_open",
+			requestBody: map[string]string{"pvzId": testPvzID},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("OpenBatch", testUserID, testPvzID).
+					Return(&model.ReturnBatch{ID: testReturnBatchID, PvzID: testPvzID, Status: model.ReceptionInProgress}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"id": testReturnBatchID, "status": "in_progress"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReturnService := new(mocks.MockReturnService)
+			tc.setupMock(MockReturnService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/return-batches", tc.requestBody)
+
+			err := handler.NewReturnHandler(MockReturnService).OpenBatch(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReturnService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReturnBatchClose_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setupMock      func(MockReturnService *mocks.MockReturnService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "no_open_batch",
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("CloseLastBatch", testUserID, testPvzID).Return(nil, service.ErrNoOpenReturnBatch)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ has no open return batch"},
+		},
+		{
+			name: "empty_batch",
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("CloseLastBatch", testUserID, testPvzID).Return(nil, service.ErrReturnBatchEmpty)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Return batch has no returns"},
+		},
+		{
+			name: "successful_close",
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("CloseLastBatch", testUserID, testPvzID).
+					Return(&model.ReturnBatch{ID: testReturnBatchID, PvzID: testPvzID, Status: model.ReceptionClose}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"id": testReturnBatchID, "status": "close"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReturnService := new(mocks.MockReturnService)
+			tc.setupMock(MockReturnService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/pvz/"+testPvzID+"/close_last_return_batch", nil)
+			c.SetParamNames("pvzId")
+			c.SetParamValues(testPvzID)
+
+			err := handler.NewReturnHandler(MockReturnService).CloseLastBatch(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReturnService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductReturn_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockReturnService *mocks.MockReturnService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_reason",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockReturnService *mocks.MockReturnService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Reason is required"},
+		},
+		{
+			name:        "unknown_reason",
+			requestBody: map[string]string{"reason": "bored"},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("Return", testUserID, testProductID, model.ReturnReason("bored"), "").
+					Return(nil, service.ErrUnknownReturnReason)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Reason must be one of 'refused', 'not_collected', 'damaged', 'wrong_item', 'customer_return'"},
+		},
+		{
+			name:        "no_open_batch",
+			requestBody: map[string]string{"reason": "refused"},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("Return", testUserID, testProductID, model.ReturnRefused, "").
+					Return(nil, service.ErrNoOpenReturnBatch)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "PVZ has no open return batch"},
+		},
+		{
+			name:        "already_returned",
+			requestBody: map[string]string{"reason": "refused"},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("Return", testUserID, testProductID, model.ReturnRefused, "").
+					Return(nil, service.ErrProductStatusTransition)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Product status does not allow this operation"},
+		},
+		{
+			name:        "successful_return",
+			requestBody: map[string]string{"reason": "damaged", "comment": "Мокрая коробка"},
+			setupMock: func(MockReturnService *mocks.MockReturnService) {
+				MockReturnService.On("Return", testUserID, testProductID, model.ReturnDamaged, "Мокрая коробка").
+					Return(&model.ProductReturn{ProductID: testProductID, BatchID: testReturnBatchID, Reason: model.ReturnDamaged}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"batchId": testReturnBatchID, "reason": "damaged"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockReturnService := new(mocks.MockReturnService)
+			tc.setupMock(MockReturnService)
+
+			c, rec := newEmployeeContext(http.MethodPost, "/products/"+testProductID+"/return", tc.requestBody)
+			c.SetParamNames("productId")
+			c.SetParamValues(testProductID)
+
+			err := handler.NewReturnHandler(MockReturnService).Return(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockReturnService.AssertExpectations(t)
+		})
+	}
+}
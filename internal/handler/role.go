@@ -0,0 +1,91 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	service service.PermissionService
+}
+
+type RoleCreateRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Permissions []model.Permission `json:"permissions"`
+}
+
+type RolePermissionsRequest struct {
+	Permissions []model.Permission `json:"permissions"`
+}
+
+func NewRoleHandler(sPS service.PermissionService) *RoleHandler {
+	return &RoleHandler{
+		service: sPS,
+	}
+}
+
+func (rh *RoleHandler) List(ctx echo.Context) error {
+	roles, err := rh.service.ListRoles()
+	if err != nil {
+		return roleError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, roles)
+}
+
+func (rh *RoleHandler) Create(ctx echo.Context) error {
+	var request RoleCreateRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Name == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Name is required"})
+	}
+
+	role, err := rh.service.CreateRole(model.UserRole(request.Name), request.Description, request.Permissions)
+	if err != nil {
+		return roleError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, role)
+}
+
+func (rh *RoleHandler) SetPermissions(ctx echo.Context) error {
+	var request RolePermissionsRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	role, err := rh.service.SetPermissions(model.UserRole(ctx.Param("role")), request.Permissions)
+	if err != nil {
+		return roleError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, role)
+}
+
+func roleError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrInvalidRoleName):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role name must be 3-32 lowercase latin letters or underscores"})
+	case deferr.Is(err, service.ErrInvalidPermission):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown permission"})
+	case deferr.Is(err, service.ErrRoleExists):
+		return ctx.JSON(http.StatusConflict, openapi.Error{Message: "Role already exists"})
+	case deferr.Is(err, service.ErrBuiltinRole):
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Builtin role cannot be modified"})
+	case deferr.Is(err, service.ErrUnknownRole):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "Role not found"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to process role"})
+	}
+}
@@ -0,0 +1,138 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleCreate_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestBody    interface{}
+		setupMock      func(MockPermissionService *mocks.MockPermissionService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_name",
+			requestBody:    map[string]interface{}{"permissions": []string{"report.view"}},
+			setupMock:      func(MockPermissionService *mocks.MockPermissionService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Name is required"},
+		},
+		{
+			name:        "unknown_permission",
+			requestBody: map[string]interface{}{"name": "courier", "permissions": []string{"pvz.destroy"}},
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("CreateRole", model.UserRole("courier"), "", []model.Permission{"pvz.destroy"}).
+					Return(nil, service.ErrInvalidPermission)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Unknown permission"},
+		},
+		{
+			name:        "role_exists",
+			requestBody: map[string]interface{}{"name": "auditor", "permissions": []string{"report.view"}},
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("CreateRole", model.RoleAuditor, "", []model.Permission{model.PermReportView}).
+					Return(nil, service.ErrRoleExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]string{"message": "Role already exists"},
+		},
+		{
+			name:        "successful_create",
+			requestBody: map[string]interface{}{"name": "courier", "description": "Курьер", "permissions": []string{"pvz.read"}},
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("CreateRole", model.UserRole("courier"), "Курьер", []model.Permission{model.PermPvzRead}).
+					Return(&model.Role{Name: "courier", Description: "Курьер", Permissions: []model.Permission{model.PermPvzRead}}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]string{"name": "courier"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPermissionService := new(mocks.MockPermissionService)
+			tc.setupMock(MockPermissionService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/roles", "", tc.requestBody)
+
+			err := handler.NewRoleHandler(MockPermissionService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPermissionService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRoleSetPermissions_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		role           string
+		setupMock      func(MockPermissionService *mocks.MockPermissionService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "builtin_role",
+			role: "moderator",
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("SetPermissions", model.RoleModerator, []model.Permission{model.PermReportView}).
+					Return(nil, service.ErrBuiltinRole)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Builtin role cannot be modified"},
+		},
+		{
+			name: "role_not_found",
+			role: "courier",
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("SetPermissions", model.UserRole("courier"), []model.Permission{model.PermReportView}).
+					Return(nil, service.ErrUnknownRole)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "Role not found"},
+		},
+		{
+			name: "successful_update",
+			role: "auditor",
+			setupMock: func(MockPermissionService *mocks.MockPermissionService) {
+				MockPermissionService.On("SetPermissions", model.RoleAuditor, []model.Permission{model.PermReportView}).
+					Return(&model.Role{Name: model.RoleAuditor, Permissions: []model.Permission{model.PermReportView}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"name": "auditor"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPermissionService := new(mocks.MockPermissionService)
+			tc.setupMock(MockPermissionService)
+
+			c, rec := newUserAdminContext(http.MethodPut, "/roles/"+tc.role+"/permissions", "",
+				map[string]interface{}{"permissions": []string{"report.view"}})
+			c.SetParamNames("role")
+			c.SetParamValues(tc.role)
+
+			err := handler.NewRoleHandler(MockPermissionService).SetPermissions(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockPermissionService.AssertExpectations(t)
+		})
+	}
+}
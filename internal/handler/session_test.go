@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSessionID = "5d3a1f2e-8b7c-4e6d-9a0b-1c2d3e4f5a6b"
+
+func TestRefresh_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		authorization  string
+		setupMock      func(MockUserService *mocks.MockUserService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:           "missing_token",
+			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "expired_session",
+			authorization: "Bearer old",
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Refresh", "old", testIP, testUserAgent).Return("", service.ErrInvalidToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   map[string]string{"message": "Token cannot be refreshed"},
+		},
+		{
+			name:          "database_error",
+			authorization: "Bearer old",
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Refresh", "old", testIP, testUserAgent).Return("", fmt.Errorf("DB connect failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"message": "Failed to refresh token"},
+		},
+		{
+			name:          "successful_refresh",
+			authorization: "Bearer old",
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Refresh", "old", testIP, testUserAgent).Return("new", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"token": "new"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			tc.setupMock(MockUserService)
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", nil)
+			req.Header.Set("User-Agent", testUserAgent)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+
+			err := handler.NewUserHandler(MockUserService).Refresh(c)
+
+			if appErr, ok := err.(*errors.AppError); ok {
+				assert.Equal(t, tc.expectedStatus, appErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, rec.Code)
+				assertMessage(t, rec, tc.expectedBody)
+			}
+
+			MockUserService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessions_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name            string
+		userID          string
+		setupMock       func(MockUserService *mocks.MockUserService)
+		expectedStatus  int
+		expectedBody    interface{}
+		expectedCurrent []bool
+	}{
+		{
+			name:           "dummy_token",
+			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Sessions are available only for registered users"},
+		},
+		{
+			name:   "marks_current_session",
+			userID: testUserID,
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("ListSessions", testUserID).Return([]model.Session{
+					{ID: testSessionID, UserID: testUserID, IP: testIP},
+					{ID: "other", UserID: testUserID, IP: "198.51.100.7"},
+				}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedCurrent: []bool{true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			tc.setupMock(MockUserService)
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/sessions", nil), rec)
+			c.Set(middleware.ContextUserID, tc.userID)
+			c.Set(middleware.ContextSessionID, testSessionID)
+
+			err := handler.NewUserHandler(MockUserService).Sessions(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			if tc.expectedCurrent != nil {
+				var sessions []handler.SessionResponse
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sessions))
+
+				current := make([]bool, 0, len(sessions))
+				for _, session := range sessions {
+					current = append(current, session.Current)
+				}
+				assert.Equal(t, tc.expectedCurrent, current)
+			}
+
+			MockUserService.AssertExpectations(t)
+		})
+	}
+}
@@ -3,8 +3,11 @@ package handler
 import (
 	deferr "errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
 	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
@@ -20,6 +23,14 @@ type UserDummyLoginResponse struct {
 	Token openapi.Token `json:"token"`
 }
 
+// UserRegisterRequest - тело /register с необязательным кодом приглашения
+type UserRegisterRequest struct {
+	Email          types.Email `json:"email"`
+	Password       string      `json:"password"`
+	Role           string      `json:"role"`
+	InvitationCode string      `json:"invitationCode"`
+}
+
 type UserRegisterResponse struct {
 	Email string           `json:"email"`
 	Role  openapi.UserRole `json:"role"`
@@ -29,6 +40,17 @@ type UserLoginResponse struct {
 	Token openapi.Token `json:"token"`
 }
 
+// SessionResponse помечает сессию, к которой относится токен запроса
+type SessionResponse struct {
+	model.Session
+	Current bool `json:"current"`
+}
+
+type UserUnlockRequest struct {
+	Email string `json:"email"`
+	IP    string `json:"ip"`
+}
+
 func NewUserHandler(sUS service.UserService) *UserHandler {
 	return &UserHandler{
 		service: sUS,
@@ -50,7 +72,7 @@ func (uc *UserHandler) DummyLogin(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role must be 'employee' or 'moderator'"})
 	}
 
-	token, err := uc.service.CreateToken(model.UserRole(request.Role))
+	token, err := uc.service.CreateToken(model.UserRole(request.Role), ctx.RealIP(), ctx.Request().UserAgent())
 	if err != nil {
 		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
 	}
@@ -59,7 +81,7 @@ func (uc *UserHandler) DummyLogin(ctx echo.Context) error {
 }
 
 func (u *UserHandler) Register(ctx echo.Context) error {
-	var request openapi.PostRegisterJSONRequestBody
+	var request UserRegisterRequest
 
 	if err := ctx.Bind(&request); err != nil {
 		// Почта проверяется регулярным выражением и отлов пустого поля будет тут
@@ -82,12 +104,25 @@ func (u *UserHandler) Register(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role is required"})
 	}
 
-	if request.Role != openapi.Employee && request.Role != openapi.Moderator {
-		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role must be 'employee' or 'moderator'"})
-	}
-
-	user, err := u.service.Register(string(request.Email), request.Password, model.UserRole(request.Role))
+	user, err := u.service.Register(string(request.Email), request.Password, model.UserRole(request.Role), request.InvitationCode)
 	if err != nil {
+		var policyErr *service.PasswordPolicyError
+		if deferr.As(err, &policyErr) {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: policyErr.Message})
+		}
+
+		if deferr.Is(err, service.ErrUnknownRole) {
+			return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown role"})
+		}
+
+		if deferr.Is(err, service.ErrInvitationRequired) {
+			return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Invitation code is required for this role"})
+		}
+
+		if deferr.Is(err, service.ErrInvalidInvitation) {
+			return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Invitation code is invalid or expired"})
+		}
+
 		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Failed to create user"})
 	}
 	if user == nil {
@@ -117,10 +152,77 @@ func (u *UserHandler) Login(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Password is required"})
 	}
 
-	token, err := u.service.Login(string(request.Email), request.Password)
+	token, err := u.service.Login(string(request.Email), request.Password, ctx.RealIP(), ctx.Request().UserAgent())
 	if err != nil {
+		var lockedErr *service.LoginLockedError
+		if deferr.As(err, &lockedErr) {
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(lockedErr.Seconds()))
+			return ctx.JSON(http.StatusTooManyRequests, openapi.Error{Message: "Too many login attempts, try again later"})
+		}
+
+		if deferr.Is(err, service.ErrUserDisabled) {
+			return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Account is disabled"})
+		}
+
 		return ctx.JSON(http.StatusUnauthorized, openapi.Error{Message: "Failed login"})
 	}
 
 	return ctx.JSON(http.StatusOK, UserLoginResponse{token})
 }
+
+// Refresh обновляет токен сессии. Токен читается сам, без middleware.Auth,
+// чтобы неудачные попытки тоже попадали в журнал входов
+func (u *UserHandler) Refresh(ctx echo.Context) error {
+	token, found := strings.CutPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !found || token == "" {
+		return errors.Unauthorized(errors.MessageUnauthorized)
+	}
+
+	newToken, err := u.service.Refresh(token, ctx.RealIP(), ctx.Request().UserAgent())
+	if deferr.Is(err, service.ErrInvalidToken) {
+		return ctx.JSON(http.StatusUnauthorized, openapi.Error{Message: "Token cannot be refreshed"})
+	} else if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to refresh token"})
+	}
+
+	return ctx.JSON(http.StatusOK, UserLoginResponse{newToken})
+}
+
+func (u *UserHandler) Sessions(ctx echo.Context) error {
+	userID := actorID(ctx)
+	if userID == "" {
+		return ctx.JSON(http.StatusForbidden, openapi.Error{Message: "Sessions are available only for registered users"})
+	}
+
+	sessions, err := u.service.ListSessions(userID)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to list sessions"})
+	}
+
+	currentID, _ := ctx.Get(middleware.ContextSessionID).(string)
+
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, SessionResponse{Session: session, Current: session.ID == currentID})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (u *UserHandler) Unlock(ctx echo.Context) error {
+	var request UserUnlockRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Email == "" && request.IP == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Email or IP is required"})
+	}
+
+	if err := u.service.Unlock(request.Email, request.IP); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to unlock"})
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
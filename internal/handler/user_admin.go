@@ -0,0 +1,164 @@
+package handler
+
+import (
+	deferr "errors"
+	"net/http"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/api/gen/openapi"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type UserAdminHandler struct {
+	service service.UserAdminService
+}
+
+type UserResponse struct {
+	ID        string           `json:"id"`
+	Email     string           `json:"email"`
+	Role      openapi.UserRole `json:"role"`
+	Disabled  bool             `json:"disabled"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type UserChangeRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func NewUserAdminHandler(sUAS service.UserAdminService) *UserAdminHandler {
+	return &UserAdminHandler{
+		service: sUAS,
+	}
+}
+
+func newUserResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      openapi.UserRole(user.Role),
+		Disabled:  user.Disabled,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func (uah *UserAdminHandler) List(ctx echo.Context) error {
+	page, limit, err := parsePagination(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	users, err := uah.service.List(ctx.QueryParam("email"), page, limit)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to list users"})
+	}
+
+	response := make([]UserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, newUserResponse(&users[i]))
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (uah *UserAdminHandler) Get(ctx echo.Context) error {
+	id, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	user, err := uah.service.Get(id)
+	if err != nil {
+		return userAdminError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+func (uah *UserAdminHandler) ChangeRole(ctx echo.Context) error {
+	id, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	var request UserChangeRoleRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Invalid request format"})
+	}
+
+	if request.Role == "" {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Role is required"})
+	}
+
+	user, err := uah.service.ChangeRole(actorID(ctx), id, model.UserRole(request.Role))
+	if err != nil {
+		return userAdminError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+func (uah *UserAdminHandler) Disable(ctx echo.Context) error {
+	return uah.setDisabled(ctx, true)
+}
+
+func (uah *UserAdminHandler) Enable(ctx echo.Context) error {
+	return uah.setDisabled(ctx, false)
+}
+
+func (uah *UserAdminHandler) setDisabled(ctx echo.Context, disabled bool) error {
+	id, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	user, err := uah.service.SetDisabled(actorID(ctx), id, disabled)
+	if err != nil {
+		return userAdminError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+func (uah *UserAdminHandler) Delete(ctx echo.Context) error {
+	id, err := userIDParam(ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: err.Error()})
+	}
+
+	if err := uah.service.Delete(actorID(ctx), id); err != nil {
+		return userAdminError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+func userIDParam(ctx echo.Context) (string, error) {
+	return parseUUID(ctx.Param("userId"), "Invalid user id")
+}
+
+func actorID(ctx echo.Context) string {
+	id, _ := ctx.Get(middleware.ContextUserID).(string)
+	return id
+}
+
+func actorRole(ctx echo.Context) model.UserRole {
+	role, _ := ctx.Get(middleware.ContextRole).(model.UserRole)
+	return role
+}
+
+func userAdminError(ctx echo.Context, err error) error {
+	switch {
+	case deferr.Is(err, service.ErrUserNotFound):
+		return ctx.JSON(http.StatusNotFound, openapi.Error{Message: "User not found"})
+	case deferr.Is(err, service.ErrUnknownRole):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Unknown role"})
+	case deferr.Is(err, service.ErrSelfModification):
+		return ctx.JSON(http.StatusBadRequest, openapi.Error{Message: "Cannot modify your own account"})
+	default:
+		return ctx.JSON(http.StatusInternalServerError, openapi.Error{Message: "Failed to update user"})
+	}
+}
@@ -0,0 +1,215 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testModeratorID = "9a0b3c2d-1e4f-4a5b-8c7d-6e5f4a3b2c1d"
+
+type UserAdminTestCase struct {
+	name           string
+	userID         string
+	query          string
+	requestBody    interface{}
+	setupMock      func(MockUserAdminService *mocks.MockUserAdminService)
+	expectedStatus int
+	expectedBody   interface{}
+}
+
+func newUserAdminContext(method, target, userID string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+	var reqBody []byte
+	if body != nil {
+		reqBody, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("userId")
+	c.SetParamValues(userID)
+	c.Set(middleware.ContextUserID, testModeratorID)
+
+	return c, rec
+}
+
+func TestUserAdminList_TableDriven(t *testing.T) {
+	testCases := []UserAdminTestCase{
+		{
+			name:           "invalid_limit",
+			query:          "?limit=100",
+			setupMock:      func(MockUserAdminService *mocks.MockUserAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "limit must be between 1 and 30"},
+		},
+		{
+			name:           "invalid_page",
+			query:          "?page=0",
+			setupMock:      func(MockUserAdminService *mocks.MockUserAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "page must be a positive integer"},
+		},
+		{
+			name:  "search_by_email",
+			query: "?email=test&page=2&limit=5",
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("List", "test", 2, 5).
+					Return([]model.User{{ID: testUserID, Email: "test@test.com", Role: model.RoleEmployee}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserAdminService := new(mocks.MockUserAdminService)
+			tc.setupMock(MockUserAdminService)
+
+			handler := handler.NewUserAdminHandler(MockUserAdminService)
+
+			c, rec := newUserAdminContext(http.MethodGet, "/users"+tc.query, "", nil)
+
+			err := handler.List(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserAdminService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserAdminChangeRole_TableDriven(t *testing.T) {
+	testCases := []UserAdminTestCase{
+		{
+			name:           "invalid_user_id",
+			userID:         "not-a-uuid",
+			requestBody:    map[string]string{"role": "moderator"},
+			setupMock:      func(MockUserAdminService *mocks.MockUserAdminService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid user id"},
+		},
+		{
+			name:        "invalid_role",
+			userID:      testUserID,
+			requestBody: map[string]string{"role": "admin"},
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("ChangeRole", testModeratorID, testUserID, model.UserRole("admin")).
+					Return(nil, service.ErrUnknownRole)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Unknown role"},
+		},
+		{
+			name:        "user_not_found",
+			userID:      testUserID,
+			requestBody: map[string]string{"role": "moderator"},
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("ChangeRole", testModeratorID, testUserID, model.RoleModerator).
+					Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "User not found"},
+		},
+		{
+			name:        "own_account",
+			userID:      testModeratorID,
+			requestBody: map[string]string{"role": "employee"},
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("ChangeRole", testModeratorID, testModeratorID, model.RoleEmployee).
+					Return(nil, service.ErrSelfModification)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Cannot modify your own account"},
+		},
+		{
+			name:        "successful_change",
+			userID:      testUserID,
+			requestBody: map[string]string{"role": "moderator"},
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("ChangeRole", testModeratorID, testUserID, model.RoleModerator).
+					Return(&model.User{ID: testUserID, Email: "test@test.com", Role: model.RoleModerator}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]string{"id": testUserID, "role": "moderator"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserAdminService := new(mocks.MockUserAdminService)
+			tc.setupMock(MockUserAdminService)
+
+			handler := handler.NewUserAdminHandler(MockUserAdminService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/users/"+tc.userID+"/role", tc.userID, tc.requestBody)
+
+			err := handler.ChangeRole(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserAdminService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserAdminDisable_TableDriven(t *testing.T) {
+	testCases := []UserAdminTestCase{
+		{
+			name:   "user_not_found",
+			userID: testUserID,
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("SetDisabled", testModeratorID, testUserID, true).
+					Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]string{"message": "User not found"},
+		},
+		{
+			name:   "successful_disable",
+			userID: testUserID,
+			setupMock: func(MockUserAdminService *mocks.MockUserAdminService) {
+				MockUserAdminService.On("SetDisabled", testModeratorID, testUserID, true).
+					Return(&model.User{ID: testUserID, Email: "test@test.com", Role: model.RoleEmployee, Disabled: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserAdminService := new(mocks.MockUserAdminService)
+			tc.setupMock(MockUserAdminService)
+
+			handler := handler.NewUserAdminHandler(MockUserAdminService)
+
+			c, rec := newUserAdminContext(http.MethodPost, "/users/"+tc.userID+"/disable", tc.userID, nil)
+
+			err := handler.Disable(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserAdminService.AssertExpectations(t)
+		})
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/et0/avito-tech-internship-spring-2025/internal/handler"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
 	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
 	"github.com/labstack/echo/v4"
@@ -22,9 +24,15 @@ type UserTestCase struct {
 	setupMock      func(MockUserService *mocks.MockUserService)
 	expectedStatus int
 	expectedBody   interface{}
+	expectedHeader map[string]string
 	expectError    bool
 }
 
+// testIP - адрес, который httptest.NewRequest подставляет в RemoteAddr
+const testIP = "192.0.2.1"
+
+const testUserAgent = "pvz-client/1.0"
+
 func TestRegister_TableDriven(t *testing.T) {
 	testCases := []UserTestCase{
 		{
@@ -63,11 +71,14 @@ func TestRegister_TableDriven(t *testing.T) {
 			expectedBody:   map[string]string{"message": "Role is required"},
 		},
 		{
-			name:           "invalid_role",
-			requestBody:    map[string]string{"email": "test@test.com", "password": "test", "role": "admin"},
-			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			name:        "invalid_role",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "admin"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Register", "test@test.com", "test", model.UserRole("admin"), "").
+					Return(nil, service.ErrUnknownRole)
+			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   map[string]string{"message": "Role must be 'employee' or 'moderator'"},
+			expectedBody:   map[string]string{"message": "Unknown role"},
 		},
 		{
 			name:           "invalid_email",
@@ -88,27 +99,57 @@ func TestRegister_TableDriven(t *testing.T) {
 			name:        "database_error",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator).
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "").
 					Return(nil, fmt.Errorf("DB connect failed"))
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]string{"message": "Failed to create user"},
 		},
+		{
+			name:        "weak_password",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "").
+					Return(nil, &service.PasswordPolicyError{Message: "Password must be at least 8 characters long"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Password must be at least 8 characters long"},
+		},
 		{
 			name:        "email_already_exists",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator).
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "").
 					Return(nil, nil)
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   map[string]string{"message": "User with this email already exists"},
 		},
 		{
-			name:        "successful_registration",
+			name:        "moderator_without_invitation",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator).
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "").
+					Return(nil, service.ErrInvitationRequired)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Invitation code is required for this role"},
+		},
+		{
+			name:        "invalid_invitation",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator", "invitationCode": "used"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "used").
+					Return(nil, service.ErrInvalidInvitation)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Invitation code is invalid or expired"},
+		},
+		{
+			name:        "successful_registration",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test", "role": "moderator", "invitationCode": "code"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Register", "test@test.com", "test", model.RoleModerator, "code").
 					Return(&model.User{Email: "test@test.com", Role: model.RoleModerator}, nil)
 			},
 			expectedStatus: http.StatusCreated,
@@ -192,7 +233,7 @@ func TestDummyLogin_TableDriven(t *testing.T) {
 			name:        "success_employee_role",
 			requestBody: map[string]string{"role": "employee"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("CreateToken", model.RoleEmployee).
+				MockUserService.On("CreateToken", model.RoleEmployee, testIP, testUserAgent).
 					Return("employee_token_123", nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -202,7 +243,7 @@ func TestDummyLogin_TableDriven(t *testing.T) {
 			name:        "success_moderator_role",
 			requestBody: map[string]string{"role": "moderator"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("CreateToken", model.RoleModerator).
+				MockUserService.On("CreateToken", model.RoleModerator, testIP, testUserAgent).
 					Return("moderator_token_456", nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -248,7 +289,7 @@ func TestDummyLogin_TableDriven(t *testing.T) {
 			name:        "service_error",
 			requestBody: map[string]string{"role": "employee"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("CreateToken", model.RoleEmployee).
+				MockUserService.On("CreateToken", model.RoleEmployee, testIP, testUserAgent).
 					Return("", fmt.Errorf("failed to generate token"))
 			},
 			// TODO: change status from StatusBadRequest to StatusInternalServerError
@@ -286,6 +327,7 @@ func TestDummyLogin_TableDriven(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/dummyLogin", bytes.NewReader(reqBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", testUserAgent)
 
 			rec := httptest.NewRecorder()
 
@@ -372,7 +414,7 @@ func TestLogin_TableDriven(t *testing.T) {
 			name:        "database_error",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Login", "test@test.com", "test").
+				MockUserService.On("Login", "test@test.com", "test", testIP, testUserAgent).
 					Return("", fmt.Errorf("DB connect failed"))
 			},
 			expectedStatus: http.StatusUnauthorized,
@@ -382,7 +424,7 @@ func TestLogin_TableDriven(t *testing.T) {
 			name:        "email_not_found",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Login", "test@test.com", "test").
+				MockUserService.On("Login", "test@test.com", "test", testIP, testUserAgent).
 					Return("", fmt.Errorf("User not found"))
 			},
 			expectedStatus: http.StatusUnauthorized,
@@ -392,17 +434,38 @@ func TestLogin_TableDriven(t *testing.T) {
 			name:        "wrong_password",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test_wrong"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Login", "test@test.com", "test_wrong").
+				MockUserService.On("Login", "test@test.com", "test_wrong", testIP, testUserAgent).
 					Return("", fmt.Errorf("Invalid credentials"))
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   map[string]string{"message": "Failed login"},
 		},
+		{
+			name:        "disabled_account",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Login", "test@test.com", "test", testIP, testUserAgent).
+					Return("", service.ErrUserDisabled)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"message": "Account is disabled"},
+		},
+		{
+			name:        "too_many_attempts",
+			requestBody: map[string]string{"email": "test@test.com", "password": "test"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Login", "test@test.com", "test", testIP, testUserAgent).
+					Return("", &service.LoginLockedError{RetryAfter: 1500 * time.Millisecond})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   map[string]string{"message": "Too many login attempts, try again later"},
+			expectedHeader: map[string]string{"Retry-After": "2"},
+		},
 		{
 			name:        "successful_login",
 			requestBody: map[string]string{"email": "test@test.com", "password": "test"},
 			setupMock: func(MockUserService *mocks.MockUserService) {
-				MockUserService.On("Login", "test@test.com", "test").
+				MockUserService.On("Login", "test@test.com", "test", testIP, testUserAgent).
 					Return("correct_token", nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -429,6 +492,7 @@ func TestLogin_TableDriven(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(reqBody))
 			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", testUserAgent)
 
 			rec := httptest.NewRecorder()
 
@@ -473,8 +537,82 @@ func TestLogin_TableDriven(t *testing.T) {
 				}
 			}
 
+			for key, expectedValue := range tc.expectedHeader {
+				assert.Equal(t, expectedValue, rec.Header().Get(key))
+			}
+
 			// Verify mock expectations
 			MockUserService.AssertExpectations(t)
 		})
 	}
 }
+
+func TestUnlock_TableDriven(t *testing.T) {
+	testCases := []UserTestCase{
+		{
+			name:           "missing_email_and_ip",
+			requestBody:    map[string]string{},
+			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Email or IP is required"},
+		},
+		{
+			name:           "invalid_json",
+			requestBody:    "invalid_json_string",
+			setupMock:      func(MockUserService *mocks.MockUserService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"message": "Invalid request format"},
+		},
+		{
+			name:        "database_error",
+			requestBody: map[string]string{"email": "test@test.com"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Unlock", "test@test.com", "").
+					Return(fmt.Errorf("DB connect failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"message": "Failed to unlock"},
+		},
+		{
+			name:        "successful_unlock",
+			requestBody: map[string]string{"email": "test@test.com", "ip": "10.0.0.1"},
+			setupMock: func(MockUserService *mocks.MockUserService) {
+				MockUserService.On("Unlock", "test@test.com", "10.0.0.1").
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			tc.setupMock(MockUserService)
+
+			handler := handler.NewUserHandler(MockUserService)
+
+			var reqBody []byte
+			if bodyStr, ok := tc.requestBody.(string); ok {
+				reqBody = []byte(bodyStr)
+			} else {
+				reqBody, _ = json.Marshal(tc.requestBody)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/users/unlock", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+
+			err := handler.Unlock(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assertMessage(t, rec, tc.expectedBody)
+
+			MockUserService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// Ключи, под которыми данные токена сохраняются в echo.Context
+const (
+	ContextRole      = "role"
+	ContextUserID    = "user_id"
+	ContextSessionID = "session_id"
+	ContextAPIKeyID  = "api_key_id"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+type TokenParser interface {
+	ParseToken(token string) (*model.TokenClaims, error)
+}
+
+type PermissionChecker interface {
+	HasPermission(role model.UserRole, permission model.Permission) (bool, error)
+}
+
+type APIKeyVerifier interface {
+	VerifyAPIKey(key string) (*model.TokenClaims, error)
+}
+
+// Auth проверяет Bearer токен и кладёт роль и id пользователя в контекст.
+// API-ключи здесь не принимаются, для них есть AuthWithAPIKey
+func Auth(parser TokenParser) echo.MiddlewareFunc {
+	return AuthWithAPIKey(parser, nil, "")
+}
+
+// AuthWithAPIKey дополнительно принимает API-ключ (в X-API-Key или как Bearer pvz_...),
+// если у ключа есть scope. Роль и id берутся у пользователя, которому выпущен ключ
+func AuthWithAPIKey(parser TokenParser, verifier APIKeyVerifier, scope model.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Request().Header.Get(HeaderAPIKey)
+			if token == "" {
+				bearer, found := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+				if !found || bearer == "" {
+					return errors.Unauthorized(errors.MessageUnauthorized)
+				}
+				token = bearer
+			}
+
+			var claims *model.TokenClaims
+			var err error
+
+			if strings.HasPrefix(token, model.APIKeyPrefix) {
+				if verifier == nil {
+					return errors.Unauthorized(errors.MessageAPIKeyNotAllowed)
+				}
+
+				claims, err = verifier.VerifyAPIKey(token)
+				if err != nil {
+					return errors.Unauthorized(errors.MessageUnauthorized)
+				}
+
+				if !slices.Contains(claims.Scopes, scope) {
+					return errors.Forbidden(errors.MessageMissingScope)
+				}
+			} else {
+				claims, err = parser.ParseToken(token)
+				if err != nil {
+					return errors.Unauthorized(errors.MessageUnauthorized)
+				}
+			}
+
+			c.Set(ContextRole, claims.Role)
+			c.Set(ContextUserID, claims.UserID)
+			c.Set(ContextSessionID, claims.SessionID)
+			c.Set(ContextAPIKeyID, claims.APIKeyID)
+
+			return next(c)
+		}
+	}
+}
+
+// RequireRole пропускает запрос дальше только для перечисленных ролей
+func RequireRole(roles ...model.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get(ContextRole).(model.UserRole)
+			if !slices.Contains(roles, role) {
+				return errors.Forbidden(errors.MessageForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequirePermission пропускает запрос, если у роли из контекста есть право permission
+func RequirePermission(checker PermissionChecker, permission model.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get(ContextRole).(model.UserRole)
+
+			allowed, err := checker.HasPermission(role, permission)
+			if err != nil {
+				return err
+			}
+
+			if !allowed {
+				return errors.Forbidden(errors.MessageForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/middleware"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/service/mocks"
+	"github.com/et0/avito-tech-internship-spring-2025/pkg/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthWithAPIKey_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		headers        map[string]string
+		setupMock      func(MockUserService *mocks.MockUserService, MockAPIKeyService *mocks.MockAPIKeyService)
+		expectedStatus int
+		expectedRole   model.UserRole
+	}{
+		{
+			name:           "missing_token",
+			setupMock:      func(*mocks.MockUserService, *mocks.MockAPIKeyService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "invalid_jwt",
+			headers: map[string]string{echo.HeaderAuthorization: "Bearer broken"},
+			setupMock: func(MockUserService *mocks.MockUserService, _ *mocks.MockAPIKeyService) {
+				MockUserService.On("ParseToken", "broken").Return(nil, fmt.Errorf("invalid token"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "valid_jwt",
+			headers: map[string]string{echo.HeaderAuthorization: "Bearer jwt"},
+			setupMock: func(MockUserService *mocks.MockUserService, _ *mocks.MockAPIKeyService) {
+				MockUserService.On("ParseToken", "jwt").Return(&model.TokenClaims{Role: model.RoleEmployee}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRole:   model.RoleEmployee,
+		},
+		{
+			name:    "api_key_without_scope",
+			headers: map[string]string{middleware.HeaderAPIKey: "pvz_abcd_secret"},
+			setupMock: func(_ *mocks.MockUserService, MockAPIKeyService *mocks.MockAPIKeyService) {
+				MockAPIKeyService.On("VerifyAPIKey", "pvz_abcd_secret").
+					Return(&model.TokenClaims{Role: model.RoleEmployee, Scopes: []model.Scope{model.ScopePvzRead}}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:    "api_key_as_bearer",
+			headers: map[string]string{echo.HeaderAuthorization: "Bearer pvz_abcd_secret"},
+			setupMock: func(_ *mocks.MockUserService, MockAPIKeyService *mocks.MockAPIKeyService) {
+				MockAPIKeyService.On("VerifyAPIKey", "pvz_abcd_secret").
+					Return(&model.TokenClaims{Role: model.RoleEmployee, Scopes: []model.Scope{model.ScopeReceptionWrite}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedRole:   model.RoleEmployee,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockUserService := new(mocks.MockUserService)
+			MockAPIKeyService := new(mocks.MockAPIKeyService)
+			tc.setupMock(MockUserService, MockAPIKeyService)
+
+			req := httptest.NewRequest(http.MethodPost, "/receptions", nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+
+			next := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+
+			err := middleware.AuthWithAPIKey(MockUserService, MockAPIKeyService, model.ScopeReceptionWrite)(next)(c)
+
+			if appErr, ok := err.(*errors.AppError); ok {
+				assert.Equal(t, tc.expectedStatus, appErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, rec.Code)
+				assert.Equal(t, tc.expectedRole, c.Get(middleware.ContextRole))
+			}
+
+			MockUserService.AssertExpectations(t)
+			MockAPIKeyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuth_RejectsAPIKey(t *testing.T) {
+	MockUserService := new(mocks.MockUserService)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set(middleware.HeaderAPIKey, "pvz_abcd_secret")
+
+	e := echo.New()
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := middleware.Auth(MockUserService)(func(c echo.Context) error { return nil })(c)
+
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, appErr.Code)
+	assert.Equal(t, errors.MessageAPIKeyNotAllowed, appErr.Message)
+}
+
+func TestRequirePermission_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		role           model.UserRole
+		allowed        bool
+		expectedStatus int
+	}{
+		{name: "auditor_cannot_open_reception", role: model.RoleAuditor, allowed: false, expectedStatus: http.StatusForbidden},
+		{name: "employee_can_open_reception", role: model.RoleEmployee, allowed: true, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			MockPermissionService := new(mocks.MockPermissionService)
+			MockPermissionService.On("HasPermission", tc.role, model.PermReceptionOpen).Return(tc.allowed, nil)
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/receptions", nil), rec)
+			c.Set(middleware.ContextRole, tc.role)
+
+			next := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+
+			err := middleware.RequirePermission(MockPermissionService, model.PermReceptionOpen)(next)(c)
+
+			if appErr, ok := err.(*errors.AppError); ok {
+				assert.Equal(t, tc.expectedStatus, appErr.Code)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, rec.Code)
+			}
+
+			MockPermissionService.AssertExpectations(t)
+		})
+	}
+}
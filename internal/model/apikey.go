@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// APIKeyPrefix - начало каждого ключа, по нему ключ отличается от JWT
+const APIKeyPrefix = "pvz_"
+
+type Scope string
+
+const (
+	ScopePvzRead        Scope = "pvz:read"
+	ScopePvzWrite       Scope = "pvz:write"
+	ScopeReceptionWrite Scope = "reception:write"
+)
+
+var Scopes = []Scope{ScopePvzRead, ScopePvzWrite, ScopeReceptionWrite}
+
+// APIKey действует от имени пользователя UserID, но только в пределах Scopes.
+// Prefix хранится открыто, чтобы ключ можно было узнать в списке, сам ключ - только хешем
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	UserID     string     `json:"userId"`
+	Scopes     []Scope    `json:"scopes"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
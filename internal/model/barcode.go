@@ -0,0 +1,9 @@
+package model
+
+import "strings"
+
+// NormalizeBarcode приводит штрихкод к виду, в котором он хранится и сравнивается:
+// без пробелов по краям и в верхнем регистре
+func NormalizeBarcode(barcode string) string {
+	return strings.ToUpper(strings.TrimSpace(barcode))
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// StorageCell - ячейка хранения на ПВЗ. Occupied считает только товары, которые
+// физически лежат на ПВЗ, то есть в статусах из ShelfProductStatuses
+type StorageCell struct {
+	ID        string    `json:"id"`
+	PvzID     string    `json:"pvzId"`
+	Code      string    `json:"code"`
+	Capacity  int       `json:"capacity"`
+	Occupied  int       `json:"occupied"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CellMove - размещение товара в ячейке. FromCellID пуст при первом размещении
+type CellMove struct {
+	ID         string    `json:"id"`
+	ProductID  string    `json:"productId"`
+	FromCellID string    `json:"fromCellId,omitempty"`
+	ToCellID   string    `json:"toCellId"`
+	MovedBy    string    `json:"movedBy,omitempty"`
+	MovedAt    time.Time `json:"movedAt"`
+}
+
+// ProductLocation отвечает на вопрос "где товар": текущая ячейка и история перемещений
+type ProductLocation struct {
+	Product Product      `json:"product"`
+	PvzID   string       `json:"pvzId"`
+	Cell    *StorageCell `json:"cell"`
+	Moves   []CellMove   `json:"moves"`
+}
+
+// NormalizeCellCode приводит код ячейки к виду, в котором он хранится: без пробелов и в верхнем регистре
+func NormalizeCellCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// City - город из справочника, в котором разрешено заводить ПВЗ, пока он активен
+type City struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CityRename - запись истории переименований города
+type CityRename struct {
+	CityID    string    `json:"cityId"`
+	OldName   string    `json:"oldName"`
+	NewName   string    `json:"newName"`
+	ChangedBy string    `json:"changedBy,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "strings"
+
+// NormalizeEmail приводит почту к виду, в котором она хранится и сравнивается:
+// без пробелов по краям и в нижнем регистре
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// EmailDuplicate - группа учётных записей, почты которых совпадают после нормализации
+type EmailDuplicate struct {
+	Normalized string
+	UserIDs    []string
+	Emails     []string
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name     string
+		email    string
+		expected string
+	}{
+		{name: "already_normalized", email: "user@example.com", expected: "user@example.com"},
+		{name: "upper_case", email: "User@Example.COM", expected: "user@example.com"},
+		{name: "surrounding_spaces", email: "  user@example.com ", expected: "user@example.com"},
+		{name: "tabs_and_newlines", email: "\tuser@example.com\r\n", expected: "user@example.com"},
+		{name: "unicode_space", email: "\u00a0user@example.com\u2003", expected: "user@example.com"},
+		{name: "unicode_upper_case", email: "ÜSER@BEISPIEL.DE", expected: "üser@beispiel.de"},
+		{name: "cyrillic", email: "Почта@Пример.РФ", expected: "почта@пример.рф"},
+		{name: "inner_space_kept", email: "us er@example.com", expected: "us er@example.com"},
+		{name: "empty", email: "   ", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeEmail(tc.email))
+		})
+	}
+}
+
+func TestNormalizeEmail_Idempotent(t *testing.T) {
+	for _, email := range []string{" User@Example.COM ", "ÜSER@BEISPIEL.DE", "\tПочта@Пример.РФ"} {
+		normalized := NormalizeEmail(email)
+
+		assert.Equal(t, normalized, NormalizeEmail(normalized))
+	}
+}
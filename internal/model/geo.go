@@ -0,0 +1,18 @@
+package model
+
+// GeoPoint - координаты в градусах WGS 84
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Valid проверяет диапазоны широты и долготы. NaN не проходит ни одно сравнение
+func (p GeoPoint) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// NearbyPvz - ПВЗ из поиска ближайших с расстоянием до точки поиска в метрах
+type NearbyPvz struct {
+	PVZ
+	Distance float64 `json:"distance"`
+}
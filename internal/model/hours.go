@@ -0,0 +1,96 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// OpeningHours - часы работы ПВЗ в день недели по местному времени, 1 - понедельник, 7 - воскресенье.
+// Opens и Closes в формате "15:04", Closes может быть "24:00"
+type OpeningHours struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// HoursException заменяет недельный график на дату. Без Opens и Closes ПВЗ в этот день не работает
+type HoursException struct {
+	Date   string `json:"date"`
+	Opens  string `json:"opens,omitempty"`
+	Closes string `json:"closes,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Closed - ПВЗ не работает весь день
+func (e HoursException) Closed() bool {
+	return e.Opens == "" && e.Closes == ""
+}
+
+// PvzSchedule - график ПВЗ. Пустой Weekly - ПВЗ работает круглосуточно, если на дату нет исключения
+type PvzSchedule struct {
+	PvzID      string           `json:"pvzId"`
+	Weekly     []OpeningHours   `json:"weekly"`
+	Exceptions []HoursException `json:"exceptions"`
+}
+
+// OpenAt проверяет, работает ли ПВЗ в момент local - время уже должно быть в часовом поясе ПВЗ
+func (s PvzSchedule) OpenAt(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+	date := local.Format(time.DateOnly)
+
+	for _, exception := range s.Exceptions {
+		if exception.Date == date {
+			return !exception.Closed() && within(minute, exception.Opens, exception.Closes)
+		}
+	}
+
+	if len(s.Weekly) == 0 {
+		return true
+	}
+
+	weekday := ISOWeekday(local.Weekday())
+	for _, hours := range s.Weekly {
+		if hours.Weekday == weekday {
+			return within(minute, hours.Opens, hours.Closes)
+		}
+	}
+
+	return false
+}
+
+// ISOWeekday переводит день недели Go, где воскресенье 0, в номер от 1 до 7
+func ISOWeekday(weekday time.Weekday) int {
+	if weekday == time.Sunday {
+		return 7
+	}
+
+	return int(weekday)
+}
+
+// ParseClock разбирает время "15:04" в минуты от начала дня. "24:00" - конец дня
+func ParseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+func within(minute int, opens, closes string) bool {
+	from, err := ParseClock(opens)
+	if err != nil {
+		return false
+	}
+
+	to, err := ParseClock(closes)
+	if err != nil {
+		return false
+	}
+
+	return minute >= from && minute < to
+}
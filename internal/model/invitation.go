@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Invitation даёт право зарегистрироваться с указанной ролью на указанную почту.
+// Сам код хранится только в виде хеша и показывается один раз при создании
+type Invitation struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Role      UserRole   `json:"role"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
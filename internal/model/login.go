@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+type ThrottleKind string
+
+const (
+	ThrottleEmail ThrottleKind = "email"
+	ThrottleIP    ThrottleKind = "ip"
+)
+
+// LoginThrottle хранит счётчик неудачных попыток входа для почты или IP
+type LoginThrottle struct {
+	Kind          ThrottleKind
+	Value         string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// TokenClaims - данные о том, кто выполняет запрос. UserID пуст у токенов из /dummyLogin,
+// APIKeyID и Scopes заполнены, только если запрос пришёл с API-ключом
+type TokenClaims struct {
+	UserID    string
+	SessionID string
+	Role      UserRole
+	APIKeyID  string
+	Scopes    []Scope
+}
+
+type LoginKind string
+
+const (
+	LoginPassword LoginKind = "login"
+	LoginDummy    LoginKind = "dummy_login"
+	LoginRefresh  LoginKind = "refresh"
+)
+
+// Причины неудачных попыток входа, попадают в login_attempts.reason
+const (
+	LoginReasonLocked          = "locked"
+	LoginReasonUnknownUser     = "unknown_user"
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonDisabled        = "disabled"
+	LoginReasonInvalidToken    = "invalid_token"
+	LoginReasonSessionExpired  = "session_expired"
+	LoginReasonInternal        = "internal_error"
+)
+
+// LoginAttempt - запись журнала входов. UserID пуст, если пользователь не найден или вход через /dummyLogin
+type LoginAttempt struct {
+	ID        string    `json:"id"`
+	Kind      LoginKind `json:"kind"`
+	UserID    string    `json:"userId,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Role      UserRole  `json:"role,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LoginAttemptFilter - фильтры журнала входов, пустые поля не применяются
+type LoginAttemptFilter struct {
+	UserID  string
+	Email   string
+	IP      string
+	Kind    LoginKind
+	Success *bool
+	From    *time.Time
+	To      *time.Time
+}
+
+// Session создаётся при входе по паролю и продлевается при обновлении токена
+type Session struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userId"`
+	IP          string    `json:"ip"`
+	UserAgent   string    `json:"userAgent"`
+	CreatedAt   time.Time `json:"createdAt"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
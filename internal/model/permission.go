@@ -0,0 +1,45 @@
+package model
+
+type Permission string
+
+const (
+	PermPvzCreate         Permission = "pvz.create"
+	PermPvzRead           Permission = "pvz.read"
+	PermPvzManage         Permission = "pvz.manage"
+	PermReceptionOpen     Permission = "reception.open"
+	PermReceptionClose    Permission = "reception.close"
+	PermReceptionReopen   Permission = "reception.reopen"
+	PermOverrideHours     Permission = "reception.override_hours"
+	PermProductAdd        Permission = "product.add"
+	PermProductDelete     Permission = "product.delete"
+	PermProductIssue      Permission = "product.issue"
+	PermProductMove       Permission = "product.move"
+	PermReportView        Permission = "report.view"
+	PermUserManage        Permission = "user.manage"
+	PermInvitationCreate  Permission = "invitation.create"
+	PermAssignmentManage  Permission = "assignment.manage"
+	PermAPIKeyManage      Permission = "apikey.manage"
+	PermRoleManage        Permission = "role.manage"
+	PermAuditView         Permission = "audit.view"
+	PermCityManage        Permission = "city.manage"
+	PermProductTypeManage Permission = "product_type.manage"
+	PermCellManage        Permission = "cell.manage"
+)
+
+// Permissions - все права, которые знает приложение. Совпадает с таблицей permissions
+var Permissions = []Permission{
+	PermPvzCreate, PermPvzRead, PermPvzManage,
+	PermReceptionOpen, PermReceptionClose, PermReceptionReopen, PermOverrideHours,
+	PermProductAdd, PermProductDelete, PermProductIssue, PermProductMove,
+	PermReportView,
+	PermUserManage, PermInvitationCreate, PermAssignmentManage, PermAPIKeyManage, PermRoleManage,
+	PermAuditView, PermCityManage, PermProductTypeManage, PermCellManage,
+}
+
+// Role связывает имя роли с набором прав. Встроенные роли нельзя менять через API
+type Role struct {
+	Name        UserRole     `json:"name"`
+	Description string       `json:"description"`
+	Builtin     bool         `json:"builtin"`
+	Permissions []Permission `json:"permissions"`
+}
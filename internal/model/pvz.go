@@ -0,0 +1,218 @@
+package model
+
+import "time"
+
+type ReceptionStatus string
+
+const (
+	ReceptionInProgress ReceptionStatus = "in_progress"
+	ReceptionClose      ReceptionStatus = "close"
+)
+
+type ProductType string
+
+// ProductStatus - этап жизни товара на ПВЗ: received -> ready -> issued / returned
+type ProductStatus string
+
+const (
+	ProductReceived ProductStatus = "received"
+	ProductReady    ProductStatus = "ready"
+	ProductIssued   ProductStatus = "issued"
+	ProductReturned ProductStatus = "returned"
+)
+
+var ProductStatuses = []ProductStatus{ProductReceived, ProductReady, ProductIssued, ProductReturned}
+
+// ShelfProductStatuses - статусы товаров, которые ещё лежат на ПВЗ и занимают место
+var ShelfProductStatuses = []ProductStatus{ProductReceived, ProductReady}
+
+// ProductCondition - состояние товара при приёмке
+type ProductCondition string
+
+const (
+	ConditionOK      ProductCondition = "ok"
+	ConditionDamaged ProductCondition = "damaged"
+	ConditionWet     ProductCondition = "wet"
+	ConditionOpened  ProductCondition = "opened"
+)
+
+var ProductConditions = []ProductCondition{ConditionOK, ConditionDamaged, ConditionWet, ConditionOpened}
+
+// ProductDimensions - габариты товара в миллиметрах
+type ProductDimensions struct {
+	LengthMm int `json:"lengthMm"`
+	WidthMm  int `json:"widthMm"`
+	HeightMm int `json:"heightMm"`
+}
+
+// Исходные типы товаров. Актуальный список - справочник product_types
+const (
+	ProductElectronics ProductType = "электроника"
+	ProductClothes     ProductType = "одежда"
+	ProductShoes       ProductType = "обувь"
+)
+
+// ProductTypeInfo - запись справочника типов товаров. Code хранится в products.type,
+// Names - отображаемые названия по языкам, например {"ru": "Обувь", "en": "Shoes"}
+type ProductTypeInfo struct {
+	Code      ProductType       `json:"code"`
+	Names     map[string]string `json:"names"`
+	Active    bool              `json:"active"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// DefaultTimezone - часовой пояс ПВЗ, если он не указан при заведении
+const DefaultTimezone = "Europe/Moscow"
+
+// PvzStatus - состояние ПВЗ: active -> suspended -> active, closed - конечное
+type PvzStatus string
+
+const (
+	PvzActive    PvzStatus = "active"
+	PvzSuspended PvzStatus = "suspended"
+	PvzClosed    PvzStatus = "closed"
+)
+
+var PvzStatuses = []PvzStatus{PvzActive, PvzSuspended, PvzClosed}
+
+type PVZ struct {
+	ID               string    `json:"id"`
+	RegistrationDate time.Time `json:"registrationDate"`
+	City             string    `json:"city"`
+	// Timezone - часовой пояс IANA, по нему считаются границы дней в фильтрах и отчётах
+	Timezone string     `json:"timezone"`
+	Name     string     `json:"name,omitempty"`
+	Address  PvzAddress `json:"address"`
+	Location *GeoPoint  `json:"location,omitempty"`
+	Status   PvzStatus  `json:"status"`
+	// StatusReason и StatusChangedAt - причина и время последней приостановки, возобновления или закрытия
+	StatusReason    string     `json:"statusReason,omitempty"`
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+	// OpenNow - работает ли ПВЗ сейчас по своему графику. Заполняется в списке ПВЗ
+	OpenNow *bool `json:"openNow,omitempty"`
+	// Capacity и Utilization заполняются в списке ПВЗ и при изменении лимитов
+	Capacity    *PvzCapacity    `json:"capacity,omitempty"`
+	Utilization *PvzUtilization `json:"utilization,omitempty"`
+}
+
+// PvzAddress - адрес ПВЗ внутри города. Пустые части не заполнены
+type PvzAddress struct {
+	PostalCode string `json:"postalCode,omitempty"`
+	Street     string `json:"street,omitempty"`
+	House      string `json:"house,omitempty"`
+}
+
+// PvzCapacity - лимиты на товары, которые сейчас на ПВЗ. Total 0 - без общего лимита,
+// в ByType только типы со своим лимитом
+type PvzCapacity struct {
+	Total  int                 `json:"total"`
+	ByType map[ProductType]int `json:"byType"`
+}
+
+// PvzUtilization - сколько товаров сейчас на ПВЗ: не выданные и возвраты, которые ждут отправки
+type PvzUtilization struct {
+	Total  int                 `json:"total"`
+	ByType map[ProductType]int `json:"byType"`
+}
+
+// PvzUpdate - изменяемые поля ПВЗ. nil - поле не меняется
+type PvzUpdate struct {
+	Name     *string
+	Address  *PvzAddress
+	Location *GeoPoint
+	Timezone *string
+}
+
+// PvzTypeCount - лимит или занятость ПВЗ по типу товара. Пустой Type - общий лимит ПВЗ
+type PvzTypeCount struct {
+	PvzID string
+	Type  ProductType
+	Count int
+}
+
+type Reception struct {
+	ID       string          `json:"id"`
+	DateTime time.Time       `json:"dateTime"`
+	PvzID    string          `json:"pvzId"`
+	Status   ReceptionStatus `json:"status"`
+	ClosedAt *time.Time      `json:"closedAt,omitempty"`
+	// FlaggedAt - когда фоновая задача пометила зависшую приёмку для разбора
+	FlaggedAt *time.Time `json:"flaggedAt,omitempty"`
+}
+
+// ReceptionAction - служебное действие с приёмкой, которое пишется в reception_events
+type ReceptionAction string
+
+const (
+	ReceptionAutoClosed ReceptionAction = "auto_closed"
+	ReceptionFlagged    ReceptionAction = "flagged"
+	ReceptionReopened   ReceptionAction = "reopened"
+)
+
+// ReceptionEvent - запись журнала приёмки. ActorID пуст у действий фоновых задач
+type ReceptionEvent struct {
+	ID          string          `json:"id"`
+	ReceptionID string          `json:"receptionId"`
+	Action      ReceptionAction `json:"action"`
+	Reason      string          `json:"reason"`
+	ActorID     string          `json:"actorId,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type Product struct {
+	ID          string        `json:"id"`
+	DateTime    time.Time     `json:"dateTime"`
+	Type        ProductType   `json:"type"`
+	ReceptionID string        `json:"receptionId"`
+	Barcode     string        `json:"barcode,omitempty"`
+	Status      ProductStatus `json:"status"`
+	CellID      string        `json:"cellId,omitempty"`
+	// StatusChangedAt - время последнего перехода, пусто у только что принятого товара
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+
+	// Необязательные сведения из приёмки. DeclaredValue - объявленная ценность в копейках
+	WeightGrams   *int               `json:"weightGrams,omitempty"`
+	Dimensions    *ProductDimensions `json:"dimensions,omitempty"`
+	DeclaredValue *int64             `json:"declaredValue,omitempty"`
+	Condition     ProductCondition   `json:"condition,omitempty"`
+	Note          string             `json:"note,omitempty"`
+}
+
+// ProductHistoryEntry - товар вместе с приёмкой, в которую он попал
+type ProductHistoryEntry struct {
+	Product
+	PvzID             string          `json:"pvzId"`
+	ReceptionDateTime time.Time       `json:"receptionDateTime"`
+	ReceptionStatus   ReceptionStatus `json:"receptionStatus"`
+}
+
+// ReceptionWithProducts и PvzListItem - элементы списка GET /pvz.
+// Партии возвратов фильтруются по тем же датам, что и приёмки
+type ReceptionWithProducts struct {
+	Reception Reception `json:"reception"`
+	Products  []Product `json:"products"`
+}
+
+type PvzListItem struct {
+	Pvz           PVZ                      `json:"pvz"`
+	Receptions    []ReceptionWithProducts  `json:"receptions"`
+	ReturnBatches []ReturnBatchWithReturns `json:"returnBatches"`
+}
+
+// PvzFilter - фильтры списка ПВЗ. From и To - включительные даты приёмки и партии возвратов
+// по местному времени ПВЗ, ProductStatus оставляет в приёмках только товары в этом статусе,
+// Status - только ПВЗ в этом статусе
+type PvzFilter struct {
+	From          *time.Time
+	To            *time.Time
+	ProductStatus ProductStatus
+	Status        PvzStatus
+}
+
+// Assignment привязывает сотрудника к ПВЗ, на котором он работает
+type Assignment struct {
+	UserID    string    `json:"userId"`
+	PvzID     string    `json:"pvzId"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// DailyReceptionStats - сводка по приёмкам одного ПВЗ за один день.
+// Товары считаются по дню приёмки, в которую они добавлены
+type DailyReceptionStats struct {
+	PvzID      string              `json:"pvzId"`
+	City       string              `json:"city"`
+	Date       string              `json:"date"`
+	Receptions int                 `json:"receptions"`
+	Products   int                 `json:"products"`
+	ByType     map[ProductType]int `json:"byType"`
+	// ByCondition - товары по состоянию при приёмке, DamagedRate - доля товаров не в состоянии ok
+	ByCondition map[ProductCondition]int `json:"byCondition"`
+	DamagedRate float64                  `json:"damagedRate"`
+}
+
+// ReportFilter - фильтры отчётов, пустые поля не применяются. To включает весь день
+type ReportFilter struct {
+	PvzID string
+	City  string
+	From  *time.Time
+	To    *time.Time
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// ReturnReason - почему товар не ушёл получателю или вернулся от него
+type ReturnReason string
+
+const (
+	ReturnRefused        ReturnReason = "refused"
+	ReturnNotCollected   ReturnReason = "not_collected"
+	ReturnDamaged        ReturnReason = "damaged"
+	ReturnWrongItem      ReturnReason = "wrong_item"
+	ReturnCustomerReturn ReturnReason = "customer_return"
+)
+
+var ReturnReasons = []ReturnReason{ReturnRefused, ReturnNotCollected, ReturnDamaged, ReturnWrongItem, ReturnCustomerReturn}
+
+// ReturnBatch - партия возвратов ПВЗ. Статусы те же, что у приёмок
+type ReturnBatch struct {
+	ID        string          `json:"id"`
+	DateTime  time.Time       `json:"dateTime"`
+	PvzID     string          `json:"pvzId"`
+	Status    ReceptionStatus `json:"status"`
+	CreatedBy string          `json:"createdBy,omitempty"`
+	ClosedAt  *time.Time      `json:"closedAt,omitempty"`
+}
+
+// ProductReturn - возврат товара, попавший в партию BatchID
+type ProductReturn struct {
+	ID        string       `json:"id"`
+	ProductID string       `json:"productId"`
+	BatchID   string       `json:"batchId"`
+	Reason    ReturnReason `json:"reason"`
+	Comment   string       `json:"comment,omitempty"`
+	CreatedBy string       `json:"createdBy,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type ReturnBatchWithReturns struct {
+	Batch   ReturnBatch     `json:"batch"`
+	Returns []ProductReturn `json:"returns"`
+}
@@ -5,14 +5,22 @@ import "time"
 type UserRole string
 
 const (
-	RoleEmployee  UserRole = "employee"
-	RoleModerator UserRole = "moderator"
+	RoleEmployee   UserRole = "employee"
+	RoleModerator  UserRole = "moderator"
+	RoleAuditor    UserRole = "auditor"
+	RoleSupervisor UserRole = "supervisor"
 )
 
+// Privileged - роли, которые нельзя получить при регистрации без приглашения
+func (r UserRole) Privileged() bool {
+	return r != RoleEmployee
+}
+
 type User struct {
 	ID        string    `json:"id"`
 	Email     string    `json:"email"`
 	Password  string    `json:"-"`
 	Role      UserRole  `json:"role"`
+	Disabled  bool      `json:"disabled"`
 	CreatedAt time.Time `json:"created_at"`
 }
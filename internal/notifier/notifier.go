@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+)
+
+const (
+	KindLog  = "log"
+	KindFile = "file"
+)
+
+// Notifier доставляет пользователю служебные сообщения (ссылки сброса пароля и т.п.)
+type Notifier interface {
+	SendPasswordReset(email, token string) error
+}
+
+func New(cfg config.Notifier, log *slog.Logger) (Notifier, error) {
+	switch cfg.Kind {
+	case "", KindLog:
+		return &LogNotifier{log: log}, nil
+	case KindFile:
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("notifier path is not set")
+		}
+		return &FileNotifier{path: cfg.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier kind: %s", cfg.Kind)
+	}
+}
+
+// LogNotifier пишет сообщения в лог приложения. Только для локальной разработки
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func (n *LogNotifier) SendPasswordReset(email, token string) error {
+	n.log.Info("password reset requested", "email", email, "token", token)
+	return nil
+}
+
+// FileNotifier дописывает сообщения в файл, откуда их удобно забирать в тестах
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (n *FileNotifier) SendPasswordReset(email, token string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\tpassword_reset\t%s\t%s\n", time.Now().Format(time.RFC3339), email, token)
+	return err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import "errors"
+
+// ErrCellFull - в ячейке хранения не осталось места. Проверка идёт под блокировкой ячейки,
+// поэтому хранилище сообщает об этом ошибкой, а не пустым результатом
+var ErrCellFull = errors.New("storage cell is full")
+
+// ErrCellCapacityBelowUsage - новая вместимость ячейки меньше числа товаров в ней, проверка идёт под той же блокировкой
+var ErrCellCapacityBelowUsage = errors.New("cell capacity is below current occupancy")
+
+// ErrPvzFull и ErrPvzTypeFull - на ПВЗ нет места под товар: исчерпан общий лимит или лимит типа
+var (
+	ErrPvzFull     = errors.New("pvz is full")
+	ErrPvzTypeFull = errors.New("pvz has no room for this product type")
+)
+
+// ErrPvzNotActive - ПВЗ приостановлен или закрыт, новые приёмки и партии возвратов на нём не открываются
+var ErrPvzNotActive = errors.New("pvz is not active")
+
+// ErrPvzHasOpenReception и ErrPvzNotEmpty - ПВЗ нельзя закрыть, пока на нём идёт приёмка или лежат товары
+var (
+	ErrPvzHasOpenReception = errors.New("pvz has an open reception")
+	ErrPvzNotEmpty         = errors.New("pvz still has products")
+)
+
+// ErrInvalidIssueCode и ErrIssueCodeLocked - код получателя не совпал или попытки ввода исчерпаны.
+// Счётчик попыток проверяется и увеличивается под блокировкой строки товара
+var (
+	ErrInvalidIssueCode = errors.New("invalid issue code")
+	ErrIssueCodeLocked  = errors.New("too many invalid issue codes")
+)
+
+// ErrBarcodeDuplicate - товар с этим штрихкодом уже принят, проверка идёт под advisory lock штрихкода
+var ErrBarcodeDuplicate = errors.New("barcode already accepted")
+
+// ErrReturnBatchEmpty - в партии нет ни одного возврата, закрывать её нечего
+var ErrReturnBatchEmpty = errors.New("return batch is empty")
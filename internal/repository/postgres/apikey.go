@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = "id,name,prefix,key_hash,user_id,scopes,COALESCE(created_by::text, ''),expires_at,last_used_at,revoked_at,created_at"
+
+func scanAPIKey(row pgx.Row) (*model.APIKey, error) {
+	var key model.APIKey
+
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.UserID, &key.Scopes,
+		&key.CreatedBy, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (p *Postgres) CreateAPIKey(key *model.APIKey) (*model.APIKey, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanAPIKey(conn.QueryRow(context.Background(),
+		`INSERT INTO api_keys (name, prefix, key_hash, user_id, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7)
+		RETURNING `+apiKeyColumns,
+		key.Name, key.Prefix, key.KeyHash, key.UserID, key.Scopes, key.CreatedBy, key.ExpiresAt,
+	))
+}
+
+// FindAPIKeyByPrefix ищет неотозванный ключ
+func (p *Postgres) FindAPIKeyByPrefix(prefix string) (*model.APIKey, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanAPIKey(conn.QueryRow(context.Background(),
+		"SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1 AND revoked_at IS NULL",
+		prefix,
+	))
+}
+
+func (p *Postgres) ListAPIKeys() ([]model.APIKey, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at DESC",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (p *Postgres) RevokeAPIKey(id string) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(),
+		"UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL",
+		id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// TouchAPIKey обновляет last_used_at не чаще раза в минуту, чтобы запросы по ключу
+// не упирались в запись одной и той же строки
+func (p *Postgres) TouchAPIKey(id string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		`UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`,
+		id,
+	)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+// CreateAssignment идемпотентна: повторное назначение ничего не меняет
+func (p *Postgres) CreateAssignment(userID, pvzID, createdBy string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		`INSERT INTO pvz_assignments (user_id, pvz_id, created_by)
+		VALUES ($1, $2, NULLIF($3, '')::uuid)
+		ON CONFLICT (user_id, pvz_id) DO NOTHING`,
+		userID, pvzID, createdBy,
+	)
+
+	return err
+}
+
+func (p *Postgres) DeleteAssignment(userID, pvzID string) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(),
+		"DELETE FROM pvz_assignments WHERE user_id = $1 AND pvz_id = $2",
+		userID, pvzID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (p *Postgres) IsAssigned(userID, pvzID string) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	var assigned bool
+
+	err = conn.QueryRow(context.Background(),
+		"SELECT EXISTS (SELECT 1 FROM pvz_assignments WHERE user_id = $1 AND pvz_id = $2)",
+		userID, pvzID,
+	).Scan(&assigned)
+
+	return assigned, err
+}
+
+// ListAssignments возвращает назначения по пользователю и/или ПВЗ, пустая строка - без фильтра
+func (p *Postgres) ListAssignments(userID, pvzID string) ([]model.Assignment, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT user_id, pvz_id, COALESCE(created_by::text, ''), created_at
+		FROM pvz_assignments
+		WHERE ($1 = '' OR user_id = NULLIF($1, '')::uuid) AND ($2 = '' OR pvz_id = NULLIF($2, '')::uuid)
+		ORDER BY created_at`,
+		userID, pvzID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []model.Assignment{}
+	for rows.Next() {
+		var assignment model.Assignment
+		if err := rows.Scan(&assignment.UserID, &assignment.PvzID, &assignment.CreatedBy, &assignment.CreatedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments, rows.Err()
+}
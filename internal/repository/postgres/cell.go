@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// cellColumns считает занятость подзапросом, запрос должен выбирать из storage_cells c
+const cellColumns = `c.id,c.pvz_id,c.code,c.capacity,
+	(SELECT COUNT(*) FROM products p WHERE p.cell_id = c.id AND p.status IN ('received', 'ready')),
+	c.created_at`
+
+const cellMoveColumns = "id,product_id,COALESCE(from_cell_id::text, ''),to_cell_id,COALESCE(moved_by::text, ''),moved_at"
+
+func scanCell(row pgx.Row) (*model.StorageCell, error) {
+	var cell model.StorageCell
+
+	err := row.Scan(&cell.ID, &cell.PvzID, &cell.Code, &cell.Capacity, &cell.Occupied, &cell.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &cell, nil
+}
+
+// CreateCell заводит ячейку на ПВЗ, nil - ячейка с таким кодом на ПВЗ уже есть
+func (p *Postgres) CreateCell(cell *model.StorageCell) (*model.StorageCell, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	created, err := scanCell(conn.QueryRow(context.Background(),
+		`WITH c AS (
+			INSERT INTO storage_cells (pvz_id, code, capacity) VALUES ($1, $2, $3)
+			RETURNING id, pvz_id, code, capacity, created_at
+		)
+		SELECT c.id, c.pvz_id, c.code, c.capacity, 0, c.created_at FROM c`,
+		cell.PvzID, cell.Code, cell.Capacity,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	}
+
+	return created, err
+}
+
+func (p *Postgres) ListCells(pvzID string) ([]model.StorageCell, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+cellColumns+" FROM storage_cells c WHERE c.pvz_id = $1 ORDER BY c.code",
+		pvzID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cells := []model.StorageCell{}
+	for rows.Next() {
+		cell, err := scanCell(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		cells = append(cells, *cell)
+	}
+
+	return cells, rows.Err()
+}
+
+func (p *Postgres) FindCell(id string) (*model.StorageCell, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanCell(conn.QueryRow(context.Background(),
+		"SELECT "+cellColumns+" FROM storage_cells c WHERE c.id = $1",
+		id,
+	))
+}
+
+func (p *Postgres) FindCellByCode(pvzID, code string) (*model.StorageCell, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanCell(conn.QueryRow(context.Background(),
+		"SELECT "+cellColumns+" FROM storage_cells c WHERE c.pvz_id = $1 AND c.code = $2",
+		pvzID, code,
+	))
+}
+
+// UpdateCellCapacity меняет вместимость ячейки. Строка ячейки блокируется, как в placeProduct,
+// поэтому параллельное размещение не переполнит ячейку. Если новая вместимость меньше числа
+// лежащих в ячейке товаров, возвращает repository.ErrCellCapacityBelowUsage
+func (p *Postgres) UpdateCellCapacity(id string, capacity int) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), "SELECT 1 FROM storage_cells WHERE id = $1 FOR UPDATE", id); err != nil {
+		return err
+	}
+
+	var occupied int
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM products WHERE cell_id = $1 AND status IN ($2, $3)",
+		id, model.ProductReceived, model.ProductReady,
+	).Scan(&occupied)
+	if err != nil {
+		return err
+	}
+
+	if capacity < occupied {
+		return repository.ErrCellCapacityBelowUsage
+	}
+
+	if _, err := tx.Exec(context.Background(), "UPDATE storage_cells SET capacity = $2 WHERE id = $1", id, capacity); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// PlaceProduct кладёт товар в ячейку и записывает перемещение.
+// Если в ячейке нет места, возвращает repository.ErrCellFull
+func (p *Postgres) PlaceProduct(productID, cellID, movedBy string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := placeProduct(tx, productID, cellID, movedBy); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// placeProduct блокирует строку ячейки, поэтому параллельные размещения
+// в одну ячейку выполняются по очереди и не превышают вместимость
+func placeProduct(tx pgx.Tx, productID, cellID, movedBy string) error {
+	var capacity int
+
+	err := tx.QueryRow(context.Background(),
+		"SELECT capacity FROM storage_cells WHERE id = $1 FOR UPDATE",
+		cellID,
+	).Scan(&capacity)
+	if err != nil {
+		return err
+	}
+
+	var occupied int
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM products WHERE cell_id = $1 AND id <> $2 AND status IN ($3, $4)",
+		cellID, productID, model.ProductReceived, model.ProductReady,
+	).Scan(&occupied)
+	if err != nil {
+		return err
+	}
+
+	if occupied >= capacity {
+		return repository.ErrCellFull
+	}
+
+	var fromCellID string
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT COALESCE(cell_id::text, '') FROM products WHERE id = $1 FOR UPDATE",
+		productID,
+	).Scan(&fromCellID)
+	if err != nil {
+		return err
+	}
+
+	if fromCellID == cellID {
+		return nil
+	}
+
+	if _, err := tx.Exec(context.Background(), "UPDATE products SET cell_id = $2 WHERE id = $1", productID, cellID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO product_cell_moves (product_id, from_cell_id, to_cell_id, moved_by)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, NULLIF($4, '')::uuid)`,
+		productID, fromCellID, cellID, movedBy,
+	)
+
+	return err
+}
+
+// ListCellMoves возвращает перемещения товара в хронологическом порядке
+func (p *Postgres) ListCellMoves(productID string) ([]model.CellMove, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+cellMoveColumns+" FROM product_cell_moves WHERE product_id = $1 ORDER BY moved_at",
+		productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	moves := []model.CellMove{}
+	for rows.Next() {
+		var move model.CellMove
+
+		if err := rows.Scan(&move.ID, &move.ProductID, &move.FromCellID, &move.ToCellID, &move.MovedBy, &move.MovedAt); err != nil {
+			return nil, err
+		}
+
+		moves = append(moves, move)
+	}
+
+	return moves, rows.Err()
+}
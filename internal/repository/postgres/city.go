@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const cityColumns = "id, name, active, created_at"
+
+func scanCity(row pgx.Row) (*model.City, error) {
+	var city model.City
+
+	if err := row.Scan(&city.ID, &city.Name, &city.Active, &city.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &city, nil
+}
+
+func (p *Postgres) ListCities(activeOnly bool) ([]model.City, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+cityColumns+" FROM cities WHERE NOT $1 OR active ORDER BY name",
+		activeOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cities := []model.City{}
+	for rows.Next() {
+		city, err := scanCity(rows)
+		if err != nil {
+			return nil, err
+		}
+		cities = append(cities, *city)
+	}
+
+	return cities, rows.Err()
+}
+
+func (p *Postgres) FindCity(id string) (*model.City, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	city, err := scanCity(conn.QueryRow(context.Background(),
+		"SELECT "+cityColumns+" FROM cities WHERE id = $1",
+		id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return city, err
+}
+
+// FindCityByName ищет город без учёта регистра
+func (p *Postgres) FindCityByName(name string) (*model.City, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	city, err := scanCity(conn.QueryRow(context.Background(),
+		"SELECT "+cityColumns+" FROM cities WHERE lower(name) = lower($1)",
+		name,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return city, err
+}
+
+// CreateCity возвращает nil, если город с таким названием уже есть
+func (p *Postgres) CreateCity(name string) (*model.City, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	city, err := scanCity(conn.QueryRow(context.Background(),
+		"INSERT INTO cities (name) VALUES ($1) RETURNING "+cityColumns,
+		name,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	}
+
+	return city, err
+}
+
+// RenameCity меняет название и пишет старое в историю в одной транзакции.
+// Возвращает nil, если новое название уже занято
+func (p *Postgres) RenameCity(id, name, changedBy string) (*model.City, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var oldName string
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT name FROM cities WHERE id = $1 FOR UPDATE",
+		id,
+	).Scan(&oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	city, err := scanCity(tx.QueryRow(context.Background(),
+		"UPDATE cities SET name = $2 WHERE id = $1 RETURNING "+cityColumns,
+		id, name,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO city_renames (city_id, old_name, new_name, changed_by)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid)`,
+		id, oldName, name, changedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return city, nil
+}
+
+func (p *Postgres) SetCityActive(id string, active bool) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE cities SET active = $2 WHERE id = $1",
+		id, active,
+	)
+
+	return err
+}
+
+func (p *Postgres) ListCityRenames(id string) ([]model.CityRename, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT city_id, old_name, new_name, COALESCE(changed_by::text, ''), changed_at
+		FROM city_renames
+		WHERE city_id = $1
+		ORDER BY changed_at`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	renames := []model.CityRename{}
+	for rows.Next() {
+		var rename model.CityRename
+		if err := rows.Scan(&rename.CityID, &rename.OldName, &rename.NewName, &rename.ChangedBy, &rename.ChangedAt); err != nil {
+			return nil, err
+		}
+		renames = append(renames, rename)
+	}
+
+	return renames, rows.Err()
+}
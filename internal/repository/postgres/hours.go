@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+// ListPvzSchedules возвращает графики ПВЗ в порядке pvzIDs. since отсекает прошедшие исключения,
+// nil - вернуть все
+func (p *Postgres) ListPvzSchedules(pvzIDs []string, since *time.Time) ([]model.PvzSchedule, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	schedules := make([]model.PvzSchedule, len(pvzIDs))
+	byPvz := make(map[string]*model.PvzSchedule, len(pvzIDs))
+	for i, id := range pvzIDs {
+		schedules[i] = model.PvzSchedule{PvzID: id, Weekly: []model.OpeningHours{}, Exceptions: []model.HoursException{}}
+		byPvz[id] = &schedules[i]
+	}
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT pvz_id, weekday, to_char(opens_at, 'HH24:MI'), to_char(closes_at, 'HH24:MI')
+		FROM pvz_opening_hours
+		WHERE pvz_id = ANY($1::uuid[])
+		ORDER BY pvz_id, weekday`,
+		pvzIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pvzID string
+			hours model.OpeningHours
+		)
+
+		if err := rows.Scan(&pvzID, &hours.Weekday, &hours.Opens, &hours.Closes); err != nil {
+			return nil, err
+		}
+
+		byPvz[pvzID].Weekly = append(byPvz[pvzID].Weekly, hours)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = conn.Query(context.Background(),
+		`SELECT pvz_id, to_char(date, 'YYYY-MM-DD'),
+			COALESCE(to_char(opens_at, 'HH24:MI'), ''), COALESCE(to_char(closes_at, 'HH24:MI'), ''), COALESCE(note, '')
+		FROM pvz_hours_exceptions
+		WHERE pvz_id = ANY($1::uuid[]) AND ($2::date IS NULL OR date >= $2::date)
+		ORDER BY pvz_id, date`,
+		pvzIDs, since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			pvzID     string
+			exception model.HoursException
+		)
+
+		if err := rows.Scan(&pvzID, &exception.Date, &exception.Opens, &exception.Closes, &exception.Note); err != nil {
+			return nil, err
+		}
+
+		byPvz[pvzID].Exceptions = append(byPvz[pvzID].Exceptions, exception)
+	}
+
+	return schedules, rows.Err()
+}
+
+// SetPvzSchedule заменяет недельный график и исключения ПВЗ целиком
+func (p *Postgres) SetPvzSchedule(schedule model.PvzSchedule) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), "DELETE FROM pvz_opening_hours WHERE pvz_id = $1", schedule.PvzID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(context.Background(), "DELETE FROM pvz_hours_exceptions WHERE pvz_id = $1", schedule.PvzID); err != nil {
+		return err
+	}
+
+	for _, hours := range schedule.Weekly {
+		_, err = tx.Exec(context.Background(),
+			"INSERT INTO pvz_opening_hours (pvz_id, weekday, opens_at, closes_at) VALUES ($1, $2, $3::time, $4::time)",
+			schedule.PvzID, hours.Weekday, hours.Opens, hours.Closes,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, exception := range schedule.Exceptions {
+		_, err = tx.Exec(context.Background(),
+			`INSERT INTO pvz_hours_exceptions (pvz_id, date, opens_at, closes_at, note)
+			VALUES ($1, $2::date, NULLIF($3, '')::time, NULLIF($4, '')::time, NULLIF($5, ''))`,
+			schedule.PvzID, exception.Date, exception.Opens, exception.Closes, exception.Note,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
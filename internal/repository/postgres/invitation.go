@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *Postgres) CreateInvitation(invitation *model.Invitation, codeHash string) (*model.Invitation, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	created := *invitation
+
+	err = conn.QueryRow(context.Background(),
+		`INSERT INTO invitations (code_hash, email, role, created_by, expires_at)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5)
+		RETURNING id, created_at`,
+		codeHash, invitation.Email, invitation.Role, invitation.CreatedBy, invitation.ExpiresAt,
+	).Scan(&created.ID, &created.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// CreateUserWithInvitation гасит приглашение и создаёт пользователя в одной транзакции.
+// Если приглашение не найдено, истекло, уже использовано или выписано на другую почту/роль,
+// пользователь не создаётся и возвращается nil
+func (p *Postgres) CreateUserWithInvitation(email, password string, role model.UserRole, codeHash string) (*model.User, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var invitationID string
+
+	err = tx.QueryRow(context.Background(),
+		`UPDATE invitations SET used_at = NOW()
+		WHERE code_hash = $1 AND lower(email) = lower($2) AND role = $3 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id`,
+		codeHash, email, role,
+	).Scan(&invitationID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	user, err := scanUser(tx.QueryRow(context.Background(),
+		"INSERT INTO users (email, password, role) VALUES ($1, $2, $3) RETURNING "+userColumns,
+		email, password, role,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		"UPDATE invitations SET used_by = $1 WHERE id = $2",
+		user.ID, invitationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (p *Postgres) CountUsersByRole(role model.UserRole) (int, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	var count int
+
+	err = conn.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM users WHERE role = $1 AND deleted_at IS NULL",
+		role,
+	).Scan(&count)
+
+	return count, err
+}
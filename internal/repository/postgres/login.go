@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *Postgres) FindLoginThrottle(kind model.ThrottleKind, value string) (*model.LoginThrottle, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	throttle := model.LoginThrottle{Kind: kind, Value: value}
+
+	err = conn.QueryRow(context.Background(),
+		"SELECT failures, last_failure_at, locked_until FROM login_throttles WHERE kind = $1 AND value = $2",
+		kind, value,
+	).Scan(&throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+// staleThrottle - счётчик устарел: блокировка не действует, последняя ошибка раньше $4. $4 NULL - не сбрасывать
+const staleThrottle = "((t.locked_until IS NULL OR t.locked_until <= $3) AND t.last_failure_at < $4::timestamptz)"
+
+// RegisterLoginFailure атомарно увеличивает счётчик неудачных попыток и возвращает новое состояние,
+// поэтому параллельные попытки не затирают друг другу инкременты. Устаревший счётчик начинается заново
+func (p *Postgres) RegisterLoginFailure(kind model.ThrottleKind, value string, now time.Time, staleBefore *time.Time) (*model.LoginThrottle, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	throttle := model.LoginThrottle{Kind: kind, Value: value}
+
+	err = conn.QueryRow(context.Background(),
+		`INSERT INTO login_throttles AS t (kind, value, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (kind, value) DO UPDATE
+		SET failures = CASE WHEN `+staleThrottle+` THEN 1 ELSE t.failures + 1 END,
+			locked_until = CASE WHEN `+staleThrottle+` THEN NULL ELSE t.locked_until END,
+			last_failure_at = $3
+		RETURNING failures, last_failure_at, locked_until`,
+		kind, value, now, staleBefore,
+	).Scan(&throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+// LockLoginThrottle блокирует попытки до until. Более поздняя блокировка не сокращается
+func (p *Postgres) LockLoginThrottle(kind model.ThrottleKind, value string, until time.Time) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE login_throttles SET locked_until = GREATEST(locked_until, $3) WHERE kind = $1 AND value = $2",
+		kind, value, until,
+	)
+
+	return err
+}
+
+func (p *Postgres) DeleteLoginThrottle(kind model.ThrottleKind, value string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"DELETE FROM login_throttles WHERE kind = $1 AND value = $2",
+		kind, value,
+	)
+
+	return err
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *Postgres) CreatePasswordResetToken(userID, tokenHash string, expiresAt time.Time) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)",
+		userID, tokenHash, expiresAt,
+	)
+
+	return err
+}
+
+// ResetPassword по токену сброса меняет пароль в одной транзакции: гасит токен и остальные
+// неиспользованные токены пользователя, записывает новый хеш и завершает все сессии.
+// Если токен не найден, истёк или уже использован, возвращается пустая строка
+func (p *Postgres) ResetPassword(tokenHash, password string) (string, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(context.Background())
+
+	var userID string
+
+	err = tx.QueryRow(context.Background(),
+		`UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`,
+		tokenHash,
+	).Scan(&userID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	_, err = tx.Exec(context.Background(),
+		"UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL",
+		userID,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if err := setPassword(tx, userID, password); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// ChangePassword записывает новый хеш пароля и завершает все сессии пользователя
+func (p *Postgres) ChangePassword(userID, password string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if err := setPassword(tx, userID, password); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// setPassword меняет пароль и истекает активные сессии: их токены и refresh перестают приниматься
+func setPassword(tx pgx.Tx, userID, password string) error {
+	if _, err := tx.Exec(context.Background(),
+		"UPDATE users SET password = $1 WHERE id = $2",
+		password, userID,
+	); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(context.Background(),
+		"UPDATE sessions SET expires_at = NOW() WHERE user_id = $1 AND expires_at > NOW()",
+		userID,
+	)
+
+	return err
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPostgres подключается к базе из TEST_DATABASE_URL с применёнными миграциями (make migration-up).
+// Гонки блокировок проверяются только на настоящем Postgres, поэтому без переменной тесты пропускаются
+func newTestPostgres(t *testing.T) *Postgres {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return &Postgres{Pool: pool}
+}
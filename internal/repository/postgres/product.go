@@ -0,0 +1,395 @@
+package postgres
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+const productColumns = "id,created_at,type,reception_id,COALESCE(barcode, ''),status,status_changed_at,COALESCE(cell_id::text, '')," +
+	"weight_grams,length_mm,width_mm,height_mm,declared_value,condition,COALESCE(note, '')"
+
+// productAliasedColumns - те же колонки для запросов, где products соединена с другими таблицами как p
+const productAliasedColumns = "p.id,p.created_at,p.type,p.reception_id,COALESCE(p.barcode, ''),p.status,p.status_changed_at," +
+	"COALESCE(p.cell_id::text, '')," +
+	"p.weight_grams,p.length_mm,p.width_mm,p.height_mm,p.declared_value,p.condition,COALESCE(p.note, '')"
+
+// productHistoryColumns - товар с приёмкой, запрос должен соединять products p и receptions r
+const productHistoryColumns = productAliasedColumns + ",r.pvz_id,r.created_at,r.status"
+
+// productDest возвращает получатели для колонок productColumns. Габариты читаются в dims
+// и переносятся в товар через setDimensions после Scan
+func productDest(product *model.Product, dims *[3]*int) []any {
+	return []any{&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.Barcode,
+		&product.Status, &product.StatusChangedAt, &product.CellID,
+		&product.WeightGrams, &dims[0], &dims[1], &dims[2], &product.DeclaredValue, &product.Condition, &product.Note}
+}
+
+func setDimensions(product *model.Product, dims [3]*int) {
+	if dims[0] == nil || dims[1] == nil || dims[2] == nil {
+		return
+	}
+
+	product.Dimensions = &model.ProductDimensions{LengthMm: *dims[0], WidthMm: *dims[1], HeightMm: *dims[2]}
+}
+
+// dimensionArgs раскладывает габариты на параметры length_mm, width_mm, height_mm
+func dimensionArgs(dimensions *model.ProductDimensions) (length, width, height *int) {
+	if dimensions == nil {
+		return nil, nil, nil
+	}
+
+	return &dimensions.LengthMm, &dimensions.WidthMm, &dimensions.HeightMm
+}
+
+func scanProduct(row pgx.Row) (*model.Product, error) {
+	var product model.Product
+	var dims [3]*int
+
+	err := row.Scan(productDest(&product, &dims)...)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	setDimensions(&product, dims)
+
+	return &product, nil
+}
+
+// CreateProduct добавляет товар в открытую приёмку ПВЗ, nil - открытой приёмки нет.
+// Штрихкод проверяется на повторный приём с barcodeSince, ячейка заполняется в той же транзакции
+func (p *Postgres) CreateProduct(pvzID, userID string, product *model.Product, barcodeSince time.Time) (*model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	if product.Barcode != "" {
+		if err := checkBarcodeAccepted(tx, product.Barcode, barcodeSince); err != nil {
+			return nil, err
+		}
+	}
+
+	length, width, height := dimensionArgs(product.Dimensions)
+
+	created, err := scanProduct(tx.QueryRow(context.Background(),
+		`INSERT INTO products (type, barcode, reception_id,
+			weight_grams, length_mm, width_mm, height_mm, declared_value, condition, note)
+		SELECT $1, NULLIF($2, ''), id, $5, $6, $7, $8, $9, $10, NULLIF($11, '')
+		FROM receptions WHERE pvz_id = $3 AND status = $4
+		RETURNING `+productColumns,
+		product.Type, product.Barcode, pvzID, model.ReceptionInProgress,
+		product.WeightGrams, length, width, height, product.DeclaredValue, product.Condition, product.Note,
+	))
+	if err != nil || created == nil {
+		return nil, err
+	}
+
+	if err := checkPvzCapacity(tx, pvzID, created.Type); err != nil {
+		return nil, err
+	}
+
+	if product.CellID != "" {
+		if err := placeProduct(tx, created.ID, product.CellID, userID); err != nil {
+			return nil, err
+		}
+
+		created.CellID = product.CellID
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// barcodeLockSpace - первый ключ advisory lock для штрихкодов, второй - hashtext(barcode)
+const barcodeLockSpace = 4001
+
+// checkBarcodeAccepted ищет товар с этим штрихкодом в открытой приёмке любого ПВЗ или в приёмке,
+// созданной не раньше since. Берёт транзакционный advisory lock на штрихкод, поэтому два параллельных
+// скана одного штрихкода не проходят проверку одновременно
+func checkBarcodeAccepted(tx pgx.Tx, barcode string, since time.Time) error {
+	if _, err := tx.Exec(context.Background(),
+		"SELECT pg_advisory_xact_lock($1, hashtext($2))",
+		barcodeLockSpace, barcode,
+	); err != nil {
+		return err
+	}
+
+	var accepted bool
+
+	err := tx.QueryRow(context.Background(),
+		`SELECT EXISTS (
+			SELECT 1 FROM products p
+			JOIN receptions r ON r.id = p.reception_id
+			WHERE p.barcode = $1 AND (r.status = $2 OR r.created_at >= $3)
+		)`,
+		barcode, model.ReceptionInProgress, since,
+	).Scan(&accepted)
+	if err != nil {
+		return err
+	}
+
+	if accepted {
+		return repository.ErrBarcodeDuplicate
+	}
+
+	return nil
+}
+
+// ListProductsByBarcode возвращает все приёмы товара с этим штрихкодом, новые первыми
+func (p *Postgres) ListProductsByBarcode(barcode string) ([]model.ProductHistoryEntry, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT `+productHistoryColumns+`
+		FROM products p
+		JOIN receptions r ON r.id = p.reception_id
+		WHERE p.barcode = $1
+		ORDER BY p.created_at DESC`,
+		barcode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []model.ProductHistoryEntry{}
+	for rows.Next() {
+		entry, err := scanProductHistoryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, *entry)
+	}
+
+	return history, rows.Err()
+}
+
+func scanProductHistoryEntry(row pgx.Row) (*model.ProductHistoryEntry, error) {
+	var entry model.ProductHistoryEntry
+	var dims [3]*int
+
+	err := row.Scan(append(productDest(&entry.Product, &dims),
+		&entry.PvzID, &entry.ReceptionDateTime, &entry.ReceptionStatus)...)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	setDimensions(&entry.Product, dims)
+
+	return &entry, nil
+}
+
+// FindProductWithReception возвращает товар вместе с ПВЗ и статусом его приёмки
+func (p *Postgres) FindProductWithReception(id string) (*model.ProductHistoryEntry, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanProductHistoryEntry(conn.QueryRow(context.Background(),
+		`SELECT `+productHistoryColumns+`
+		FROM products p
+		JOIN receptions r ON r.id = p.reception_id
+		WHERE p.id = $1`,
+		id,
+	))
+}
+
+// ListProducts возвращает товары ПВЗ, новые первыми. Пустой status - товары в любом статусе
+func (p *Postgres) ListProducts(pvzID string, status model.ProductStatus, limit, offset int) ([]model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT `+productAliasedColumns+`
+		FROM products p
+		JOIN receptions r ON r.id = p.reception_id
+		WHERE r.pvz_id = $1 AND ($2 = '' OR p.status = $2)
+		ORDER BY p.created_at DESC
+		LIMIT $3 OFFSET $4`,
+		pvzID, status, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectProducts(rows)
+}
+
+// ListProductsByReceptions возвращает товары приёмок в порядке добавления
+func (p *Postgres) ListProductsByReceptions(receptionIDs []string, status model.ProductStatus) ([]model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+productColumns+` FROM products
+		WHERE reception_id = ANY($1::uuid[]) AND ($2 = '' OR status = $2)
+		ORDER BY created_at`,
+		receptionIDs, status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectProducts(rows)
+}
+
+func collectProducts(rows pgx.Rows) ([]model.Product, error) {
+	defer rows.Close()
+
+	products := []model.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, *product)
+	}
+
+	return products, rows.Err()
+}
+
+// MarkProductReady переводит принятый товар в ready с новым кодом получателя.
+// Повторный вызов для ready-товара заменяет код и сбрасывает счётчик попыток. nil - статус не подходит
+func (p *Postgres) MarkProductReady(id, codeHash string) (*model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanProduct(conn.QueryRow(context.Background(),
+		`UPDATE products SET status = $2, issue_code_hash = $3, issue_attempts = 0, status_changed_at = NOW()
+		WHERE id = $1 AND status IN ($4, $2)
+		RETURNING `+productColumns,
+		id, model.ProductReady, codeHash, model.ProductReceived,
+	))
+}
+
+// IssueProduct выдаёт ready-товар, если код совпал и попытки не исчерпаны. Строка товара блокируется,
+// поэтому параллельные попытки с неверным кодом не проскакивают мимо лимита.
+// nil - товар уже не в статусе ready
+func (p *Postgres) IssueProduct(id, codeHash, issuedBy string, maxAttempts int) (*model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var storedHash string
+	var attempts int
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT COALESCE(issue_code_hash, ''), issue_attempts FROM products WHERE id = $1 AND status = $2 FOR UPDATE",
+		id, model.ProductReady,
+	).Scan(&storedHash, &attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if attempts >= maxAttempts {
+		return nil, repository.ErrIssueCodeLocked
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(codeHash)) != 1 {
+		if _, err := tx.Exec(context.Background(),
+			"UPDATE products SET issue_attempts = issue_attempts + 1 WHERE id = $1",
+			id,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := tx.Commit(context.Background()); err != nil {
+			return nil, err
+		}
+
+		if attempts+1 >= maxAttempts {
+			return nil, repository.ErrIssueCodeLocked
+		}
+
+		return nil, repository.ErrInvalidIssueCode
+	}
+
+	product, err := scanProduct(tx.QueryRow(context.Background(),
+		`UPDATE products SET status = $2, issued_by = NULLIF($3, '')::uuid, issue_code_hash = NULL, status_changed_at = NOW()
+		WHERE id = $1
+		RETURNING `+productColumns,
+		id, model.ProductIssued, issuedBy,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// DeleteLastProduct удаляет последний добавленный товар открытой приёмки (LIFO), nil - удалять нечего.
+// Удаляются только товары в статусе received: после переоткрытия приёмки в ней не должно быть выданных
+// или возвращённых товаров, но запрос не полагается на это
+func (p *Postgres) DeleteLastProduct(pvzID string) (*model.Product, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanProduct(conn.QueryRow(context.Background(),
+		`DELETE FROM products WHERE id = (
+			SELECT p.id FROM products p
+			JOIN receptions r ON r.id = p.reception_id
+			WHERE r.pvz_id = $1 AND r.status = $2 AND p.status = $3
+			ORDER BY p.created_at DESC
+			LIMIT 1
+		)
+		RETURNING `+productColumns,
+		pvzID, model.ReceptionInProgress, model.ProductReceived,
+	))
+}
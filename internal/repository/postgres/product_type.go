@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const productTypeColumns = "code, names, active, created_at"
+
+func scanProductType(row pgx.Row) (*model.ProductTypeInfo, error) {
+	var productType model.ProductTypeInfo
+
+	if err := row.Scan(&productType.Code, &productType.Names, &productType.Active, &productType.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return &productType, nil
+}
+
+func (p *Postgres) ListProductTypes(activeOnly bool) ([]model.ProductTypeInfo, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+productTypeColumns+" FROM product_types WHERE NOT $1 OR active ORDER BY created_at, code",
+		activeOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	productTypes := []model.ProductTypeInfo{}
+	for rows.Next() {
+		productType, err := scanProductType(rows)
+		if err != nil {
+			return nil, err
+		}
+		productTypes = append(productTypes, *productType)
+	}
+
+	return productTypes, rows.Err()
+}
+
+func (p *Postgres) FindProductType(code model.ProductType) (*model.ProductTypeInfo, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	productType, err := scanProductType(conn.QueryRow(context.Background(),
+		"SELECT "+productTypeColumns+" FROM product_types WHERE code = $1",
+		code,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return productType, err
+}
+
+// CreateProductType возвращает nil, если тип с таким кодом уже есть
+func (p *Postgres) CreateProductType(productType *model.ProductTypeInfo) (*model.ProductTypeInfo, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	created, err := scanProductType(conn.QueryRow(context.Background(),
+		"INSERT INTO product_types (code, names) VALUES ($1, $2) RETURNING "+productTypeColumns,
+		productType.Code, productType.Names,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	}
+
+	return created, err
+}
+
+func (p *Postgres) UpdateProductTypeNames(code model.ProductType, names map[string]string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE product_types SET names = $2 WHERE code = $1",
+		code, names,
+	)
+
+	return err
+}
+
+func (p *Postgres) SetProductTypeActive(code model.ProductType, active bool) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE product_types SET active = $2 WHERE code = $1",
+		code, active,
+	)
+
+	return err
+}
@@ -0,0 +1,523 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+const pvzColumns = `id, created_at, city, timezone, name, address_postal_code, address_street, address_house,
+	latitude, longitude, status, COALESCE(status_reason, ''), status_changed_at`
+
+// scanPvz читает pvzColumns и следующие за ними колонки в extra
+func scanPvz(row pgx.Row, extra ...any) (*model.PVZ, error) {
+	var (
+		pvz      model.PVZ
+		lat, lon *float64
+	)
+
+	dest := append([]any{&pvz.ID, &pvz.RegistrationDate, &pvz.City, &pvz.Timezone, &pvz.Name,
+		&pvz.Address.PostalCode, &pvz.Address.Street, &pvz.Address.House,
+		&lat, &lon, &pvz.Status, &pvz.StatusReason, &pvz.StatusChangedAt}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if lat != nil && lon != nil {
+		pvz.Location = &model.GeoPoint{Lat: *lat, Lon: *lon}
+	}
+
+	return &pvz, nil
+}
+
+// locationArgs раскладывает координаты на параметры запроса, nil - координат нет
+func locationArgs(location *model.GeoPoint) (lat, lon *float64) {
+	if location == nil {
+		return nil, nil
+	}
+
+	return &location.Lat, &location.Lon
+}
+
+func (p *Postgres) PvzExists(id string) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	var exists bool
+
+	err = conn.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM pvz WHERE id = $1)", id).Scan(&exists)
+
+	return exists, err
+}
+
+// CreatePvz сохраняет ПВЗ. Пустые id и дата регистрации заполняются базой.
+// Возвращает nil, если ПВЗ с таким id уже есть
+func (p *Postgres) CreatePvz(pvz *model.PVZ) (*model.PVZ, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	var registrationDate *time.Time
+	if !pvz.RegistrationDate.IsZero() {
+		registrationDate = &pvz.RegistrationDate
+	}
+
+	lat, lon := locationArgs(pvz.Location)
+
+	created, err := scanPvz(conn.QueryRow(context.Background(),
+		`INSERT INTO pvz (id, created_at, city, timezone, name,
+			address_postal_code, address_street, address_house, latitude, longitude)
+		VALUES (COALESCE(NULLIF($1, '')::uuid, gen_random_uuid()), COALESCE($2, NOW()), $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+pvzColumns,
+		pvz.ID, registrationDate, pvz.City, pvz.Timezone, pvz.Name,
+		pvz.Address.PostalCode, pvz.Address.Street, pvz.Address.House, lat, lon,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	}
+
+	return created, err
+}
+
+// ListPvz возвращает ПВЗ в порядке регистрации. Если задан диапазон дат,
+// остаются только ПВЗ, у которых есть приёмки в этом диапазоне. Даты включительные
+// и считаются по местному времени ПВЗ
+func (p *Postgres) ListPvz(filter model.PvzFilter, limit, offset int) ([]model.PVZ, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+pvzColumns+` FROM pvz p
+		WHERE ($5 = '' OR p.status = $5)
+			AND (($1::date IS NULL AND $2::date IS NULL) OR EXISTS (
+				SELECT 1 FROM receptions r
+				WHERE r.pvz_id = p.id
+					AND (r.created_at AT TIME ZONE p.timezone)::date
+						BETWEEN COALESCE($1::date, '-infinity') AND COALESCE($2::date, 'infinity')
+			))
+		ORDER BY created_at, id
+		LIMIT $3 OFFSET $4`,
+		filter.From, filter.To, limit, offset, filter.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pvzs := []model.PVZ{}
+	for rows.Next() {
+		pvz, err := scanPvz(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		pvzs = append(pvzs, *pvz)
+	}
+
+	return pvzs, rows.Err()
+}
+
+// ListReceptions возвращает приёмки ПВЗ из диапазона дат, новые первыми.
+// Даты включительные и считаются по местному времени ПВЗ
+func (p *Postgres) ListReceptions(pvzIDs []string, from, to *time.Time) ([]model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+receptionColumns+` FROM receptions
+		WHERE pvz_id = ANY($1::uuid[])
+			AND (($2::date IS NULL AND $3::date IS NULL)
+				OR (created_at AT TIME ZONE (SELECT p.timezone FROM pvz p WHERE p.id = pvz_id))::date
+					BETWEEN COALESCE($2::date, '-infinity') AND COALESCE($3::date, 'infinity'))
+		ORDER BY created_at DESC`,
+		pvzIDs, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receptions := []model.Reception{}
+	for rows.Next() {
+		reception, err := scanReception(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		receptions = append(receptions, *reception)
+	}
+
+	return receptions, rows.Err()
+}
+
+func (p *Postgres) FindPvz(id string) (*model.PVZ, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	pvz, err := scanPvz(conn.QueryRow(context.Background(),
+		"SELECT "+pvzColumns+" FROM pvz WHERE id = $1",
+		id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return pvz, err
+}
+
+// UpdatePvz сохраняет название, адрес, координаты и часовой пояс. Возвращает nil, если ПВЗ нет
+func (p *Postgres) UpdatePvz(pvz *model.PVZ) (*model.PVZ, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	lat, lon := locationArgs(pvz.Location)
+
+	updated, err := scanPvz(conn.QueryRow(context.Background(),
+		`UPDATE pvz
+		SET name = $2, timezone = $3, address_postal_code = $4, address_street = $5, address_house = $6,
+			latitude = $7, longitude = $8
+		WHERE id = $1
+		RETURNING `+pvzColumns,
+		pvz.ID, pvz.Name, pvz.Timezone, pvz.Address.PostalCode, pvz.Address.Street, pvz.Address.House, lat, lon,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return updated, err
+}
+
+// ChangePvzStatus переводит ПВЗ в статус to, если текущий статус входит в from.
+// Возвращает nil, если статус успел поменяться. Закрытие проверяет под блокировкой ПВЗ,
+// что нет открытой приёмки и товаров - та же блокировка берётся при добавлении товара
+func (p *Postgres) ChangePvzStatus(pvzID string, from []model.PvzStatus, to model.PvzStatus, reason, changedBy string) (*model.PVZ, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var allowed bool
+
+	err = tx.QueryRow(context.Background(),
+		"SELECT status = ANY($2) FROM pvz WHERE id = $1 FOR NO KEY UPDATE",
+		pvzID, from,
+	).Scan(&allowed)
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && !allowed {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if to == model.PvzClosed {
+		if err := checkPvzEmpty(tx, pvzID); err != nil {
+			return nil, err
+		}
+	}
+
+	pvz, err := scanPvz(tx.QueryRow(context.Background(),
+		`UPDATE pvz
+		SET status = $2, status_reason = NULLIF($3, ''), status_changed_at = NOW(), status_changed_by = NULLIF($4, '')::uuid
+		WHERE id = $1
+		RETURNING `+pvzColumns,
+		pvzID, to, reason, changedBy,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return pvz, nil
+}
+
+func checkPvzEmpty(tx pgx.Tx, pvzID string) error {
+	var hasReception, hasProducts bool
+
+	err := tx.QueryRow(context.Background(),
+		`SELECT
+			EXISTS (SELECT 1 FROM receptions WHERE pvz_id = $1 AND status = $2),
+			EXISTS (
+				SELECT 1 FROM products pr
+				JOIN receptions r ON r.id = pr.reception_id
+				WHERE r.pvz_id = $1 AND `+onPvzCondition+`
+			)`,
+		pvzID, model.ReceptionInProgress,
+	).Scan(&hasReception, &hasProducts)
+	if err != nil {
+		return err
+	}
+
+	if hasReception {
+		return repository.ErrPvzHasOpenReception
+	}
+
+	if hasProducts {
+		return repository.ErrPvzNotEmpty
+	}
+
+	return nil
+}
+
+// earthRadius - средний радиус Земли в метрах. То же значение зашито в distanceSQL
+const earthRadius = 6371000.0
+
+// distanceSQL - расстояние в метрах от точки ($1, $2) до ПВЗ по формуле гаверсинусов.
+// least защищает asin от значений чуть больше 1 из-за погрешности вычислений
+const distanceSQL = `2 * 6371000 * asin(least(1, sqrt(
+	power(sin(radians(latitude - $1) / 2), 2) +
+	cos(radians($1)) * cos(radians(latitude)) * power(sin(radians(longitude - $2) / 2), 2)
+)))`
+
+// NearbyPvz ищет активные ПВЗ в радиусе radius метров, ближние первыми.
+// Прямоугольник из boundingBox отсекает строки по индексу pvz_location,
+// точное расстояние считается только для оставшихся. Статус задан литералом,
+// чтобы условие совпадало с условием частичного индекса
+func (p *Postgres) NearbyPvz(center model.GeoPoint, radius float64, limit, offset int) ([]model.NearbyPvz, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	minLat, maxLat, minLon, maxLon := boundingBox(center, radius)
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+pvzColumns+", "+distanceSQL+` AS distance
+		FROM pvz
+		WHERE status = 'active' AND latitude IS NOT NULL
+			AND latitude BETWEEN $3 AND $4
+			AND longitude BETWEEN $5 AND $6
+			AND `+distanceSQL+` <= $7
+		ORDER BY distance, id
+		LIMIT $8 OFFSET $9`,
+		center.Lat, center.Lon, minLat, maxLat, minLon, maxLon, radius, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.NearbyPvz{}
+	for rows.Next() {
+		var distance float64
+
+		pvz, err := scanPvz(rows, &distance)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, model.NearbyPvz{PVZ: *pvz, Distance: distance})
+	}
+
+	return list, rows.Err()
+}
+
+// boundingBox возвращает прямоугольник, в который целиком попадает круг радиуса radius метров.
+// Если круг задевает полюс или линию перемены дат, долгота не ограничивается
+func boundingBox(center model.GeoPoint, radius float64) (minLat, maxLat, minLon, maxLon float64) {
+	angle := radius / earthRadius
+	dLat := angle * 180 / math.Pi
+
+	minLat, maxLat = center.Lat-dLat, center.Lat+dLat
+	if minLat <= -90 || maxLat >= 90 {
+		return max(minLat, -90), min(maxLat, 90), -180, 180
+	}
+
+	ratio := math.Sin(angle) / math.Cos(center.Lat*math.Pi/180)
+	if ratio >= 1 {
+		return minLat, maxLat, -180, 180
+	}
+
+	dLon := math.Asin(ratio) * 180 / math.Pi
+
+	minLon, maxLon = center.Lon-dLon, center.Lon+dLon
+	if minLon < -180 || maxLon > 180 {
+		return minLat, maxLat, -180, 180
+	}
+
+	return minLat, maxLat, minLon, maxLon
+}
+
+// SetPvzCapacity заменяет общий лимит и лимиты по типам целиком
+func (p *Postgres) SetPvzCapacity(pvzID string, capacity model.PvzCapacity) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = tx.Exec(context.Background(),
+		"UPDATE pvz SET capacity = NULLIF($2, 0) WHERE id = $1",
+		pvzID, capacity.Total,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(context.Background(), "DELETE FROM pvz_type_capacities WHERE pvz_id = $1", pvzID); err != nil {
+		return err
+	}
+
+	for productType, limit := range capacity.ByType {
+		_, err = tx.Exec(context.Background(),
+			"INSERT INTO pvz_type_capacities (pvz_id, type, capacity) VALUES ($1, $2, $3)",
+			pvzID, productType, limit,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (p *Postgres) ListPvzCapacities(pvzIDs []string) ([]model.PvzTypeCount, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT id, '', capacity FROM pvz WHERE id = ANY($1::uuid[]) AND capacity IS NOT NULL
+		UNION ALL
+		SELECT pvz_id, type, capacity FROM pvz_type_capacities WHERE pvz_id = ANY($1::uuid[])`,
+		pvzIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPvzTypeCounts(rows)
+}
+
+// ListPvzUsage считает товары, которые сейчас на ПВЗ, по типам
+func (p *Postgres) ListPvzUsage(pvzIDs []string) ([]model.PvzTypeCount, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT r.pvz_id, pr.type, COUNT(*)
+		FROM products pr
+		JOIN receptions r ON r.id = pr.reception_id
+		WHERE r.pvz_id = ANY($1::uuid[]) AND `+onPvzCondition+`
+		GROUP BY r.pvz_id, pr.type`,
+		pvzIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPvzTypeCounts(rows)
+}
+
+func scanPvzTypeCounts(rows pgx.Rows) ([]model.PvzTypeCount, error) {
+	counts := []model.PvzTypeCount{}
+	for rows.Next() {
+		var count model.PvzTypeCount
+		if err := rows.Scan(&count.PvzID, &count.Type, &count.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// onPvzCondition - товар pr сейчас на ПВЗ: ещё не выдан и не возвращён,
+// либо возвращён и ждёт отправки в открытой партии возвратов
+const onPvzCondition = `(pr.status IN ('received', 'ready') OR pr.status = 'returned' AND EXISTS (
+	SELECT 1 FROM product_returns ret
+	JOIN return_batches b ON b.id = ret.batch_id
+	WHERE ret.product_id = pr.id AND b.status = 'in_progress'
+))`
+
+// checkPvzCapacity вызывается после вставки товара, поэтому он уже учтён в занятости.
+// Строка ПВЗ блокируется, чтобы параллельные добавления не превысили лимит
+func checkPvzCapacity(tx pgx.Tx, pvzID string, productType model.ProductType) error {
+	var total, typeTotal int
+
+	err := tx.QueryRow(context.Background(),
+		`SELECT COALESCE(p.capacity, 0), COALESCE(c.capacity, 0)
+		FROM pvz p
+		LEFT JOIN pvz_type_capacities c ON c.pvz_id = p.id AND c.type = $2
+		WHERE p.id = $1
+		FOR NO KEY UPDATE OF p`,
+		pvzID, productType,
+	).Scan(&total, &typeTotal)
+	if err != nil {
+		return err
+	}
+
+	if total == 0 && typeTotal == 0 {
+		return nil
+	}
+
+	var used, typeUsed int
+
+	err = tx.QueryRow(context.Background(),
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE pr.type = $2)
+		FROM products pr
+		JOIN receptions r ON r.id = pr.reception_id
+		WHERE r.pvz_id = $1 AND `+onPvzCondition,
+		pvzID, productType,
+	).Scan(&used, &typeUsed)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 && used > total {
+		return repository.ErrPvzFull
+	}
+
+	if typeTotal > 0 && typeUsed > typeTotal {
+		return repository.ErrPvzTypeFull
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Код ошибки Postgres unique_violation
+const uniqueViolation = "23505"
+
+const receptionColumns = "id,created_at,pvz_id,status,closed_at,flagged_at"
+
+func scanReception(row pgx.Row) (*model.Reception, error) {
+	var reception model.Reception
+
+	err := row.Scan(&reception.ID, &reception.DateTime, &reception.PvzID, &reception.Status, &reception.ClosedAt, &reception.FlaggedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &reception, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// CreateReception открывает приёмку. Если на ПВЗ уже есть открытая приёмка,
+// срабатывает индекс unique_active_reception и возвращается nil.
+// Строка ПВЗ читается FOR SHARE, чтобы приёмка не открылась параллельно с закрытием ПВЗ
+func (p *Postgres) CreateReception(pvzID string) (*model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	reception, err := scanReception(conn.QueryRow(context.Background(),
+		`INSERT INTO receptions (pvz_id, status)
+		SELECT id, $2 FROM pvz WHERE id = $1 AND status = $3 FOR SHARE
+		RETURNING `+receptionColumns,
+		pvzID, model.ReceptionInProgress, model.PvzActive,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if reception == nil {
+		return nil, repository.ErrPvzNotActive
+	}
+
+	return reception, nil
+}
+
+func (p *Postgres) FindOpenReception(pvzID string) (*model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanReception(conn.QueryRow(context.Background(),
+		"SELECT "+receptionColumns+" FROM receptions WHERE pvz_id = $1 AND status = $2",
+		pvzID, model.ReceptionInProgress,
+	))
+}
+
+// CloseReception закрывает открытую приёмку ПВЗ, nil - открытой приёмки нет
+func (p *Postgres) CloseReception(pvzID string) (*model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanReception(conn.QueryRow(context.Background(),
+		"UPDATE receptions SET status = $1, closed_at = NOW() WHERE pvz_id = $2 AND status = $3 RETURNING "+receptionColumns,
+		model.ReceptionClose, pvzID, model.ReceptionInProgress,
+	))
+}
+
+// staleReceptionsLock - ключ advisory lock задачи зависших приёмок, общий для всех реплик
+const staleReceptionsLock = 4801
+
+// ProcessStaleReceptions закрывает или помечает приёмки, открытые раньше before, и пишет причину.
+// Для переоткрытой приёмки время считается от переоткрытия. Пустая приёмка только помечается
+// и при action close: закрывать приёмку без товаров бессмысленно. Действие над каждой приёмкой
+// пишется в reception_events. Задача берёт транзакционный advisory lock: если его держит другая реплика,
+// ничего не делает и возвращает false
+func (p *Postgres) ProcessStaleReceptions(before time.Time, action model.ReceptionAction, reason string) ([]model.Reception, bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var locked bool
+
+	if err := tx.QueryRow(context.Background(), "SELECT pg_try_advisory_xact_lock($1)", staleReceptionsLock).Scan(&locked); err != nil {
+		return nil, false, err
+	}
+
+	if !locked {
+		return nil, false, nil
+	}
+
+	rows, err := tx.Query(context.Background(),
+		`WITH candidates AS (
+			SELECT r.id AS reception_id,
+				$3::text = $4::text AND EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = r.id) AS close_it
+			FROM receptions r
+			WHERE r.status = $2 AND COALESCE(r.reopened_at, r.created_at) < $1
+		), stale AS (
+			UPDATE receptions r
+			SET status = CASE WHEN c.close_it THEN $5 ELSE r.status END,
+				closed_at = CASE WHEN c.close_it THEN NOW() ELSE r.closed_at END,
+				flagged_at = CASE WHEN c.close_it THEN r.flagged_at ELSE NOW() END
+			FROM candidates c
+			WHERE r.id = c.reception_id AND (c.close_it OR r.flagged_at IS NULL)
+			RETURNING `+receptionColumns+`
+		), events AS (
+			INSERT INTO reception_events (reception_id, action, reason)
+			SELECT id, CASE WHEN status = $5 THEN $4::text ELSE $7::text END, $6 FROM stale
+		)
+		SELECT `+receptionColumns+` FROM stale ORDER BY created_at`,
+		before, model.ReceptionInProgress, action, model.ReceptionAutoClosed, model.ReceptionClose, reason,
+		model.ReceptionFlagged,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	receptions := []model.Reception{}
+	for rows.Next() {
+		reception, err := scanReception(rows)
+		if err != nil {
+			rows.Close()
+			return nil, false, err
+		}
+
+		receptions = append(receptions, *reception)
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, false, err
+	}
+
+	return receptions, true, nil
+}
+
+// FindLastReception возвращает последнюю по времени создания приёмку ПВЗ, открытую или закрытую
+func (p *Postgres) FindLastReception(pvzID string) (*model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanReception(conn.QueryRow(context.Background(),
+		"SELECT "+receptionColumns+" FROM receptions WHERE pvz_id = $1 ORDER BY created_at DESC LIMIT 1",
+		pvzID,
+	))
+}
+
+// ReopenReception снова открывает закрытую приёмку и пишет причину в reception_events.
+// Возвращает nil, если приёмка уже не последняя на ПВЗ, не закрыта, закрыта раньше closedAfter,
+// ПВЗ закрыт или какой-то товар приёмки ушёл из статуса received. Если на ПВЗ успели открыть другую приёмку, срабатывает unique_active_reception
+// и тоже возвращается nil
+func (p *Postgres) ReopenReception(id string, closedAfter time.Time, actorID, reason string) (*model.Reception, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	reception, err := scanReception(conn.QueryRow(context.Background(),
+		`WITH reopened AS (
+			UPDATE receptions r
+			SET status = $3, closed_at = NULL, reopened_at = NOW(), flagged_at = NULL
+			WHERE r.id = $1 AND r.status = $4 AND r.closed_at >= $2
+				AND NOT EXISTS (
+					SELECT 1 FROM receptions newer
+					WHERE newer.pvz_id = r.pvz_id AND newer.created_at > r.created_at
+				)
+				AND EXISTS (SELECT 1 FROM pvz WHERE pvz.id = r.pvz_id AND pvz.status <> $7 FOR SHARE)
+				AND NOT EXISTS (SELECT 1 FROM products pr WHERE pr.reception_id = r.id AND pr.status <> $9)
+			RETURNING `+receptionColumns+`
+		), events AS (
+			INSERT INTO reception_events (reception_id, action, reason, actor_id)
+			SELECT id, $5, $6, NULLIF($8, '')::uuid FROM reopened
+		)
+		SELECT `+receptionColumns+` FROM reopened`,
+		id, closedAfter, model.ReceptionInProgress, model.ReceptionClose, model.ReceptionReopened, reason, model.PvzClosed, actorID,
+		model.ProductReceived,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	}
+
+	return reception, err
+}
+
+func (p *Postgres) ListReceptionEvents(receptionID string) ([]model.ReceptionEvent, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT id, reception_id, action, reason, COALESCE(actor_id::text, ''), created_at
+		FROM reception_events
+		WHERE reception_id = $1
+		ORDER BY created_at`,
+		receptionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.ReceptionEvent{}
+	for rows.Next() {
+		var event model.ReceptionEvent
+		if err := rows.Scan(&event.ID, &event.ReceptionID, &event.Action, &event.Reason, &event.ActorID, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
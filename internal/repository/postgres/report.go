@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+// DailyReceptionStats считает приёмки и товары по ПВЗ и дням одним запросом:
+// приёмки и товары по типам агрегируются отдельно, чтобы соединение не размножало строки.
+// Доля повреждённых считается по товарам в состоянии, отличном от ok.
+// День и границы from/to берутся в часовом поясе ПВЗ, а не сервера. Грубое условие
+// с запасом в двое суток оставляет индекс по created_at применимым до точной проверки
+func (p *Postgres) DailyReceptionStats(filter model.ReportFilter, limit, offset int) ([]model.DailyReceptionStats, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`WITH filtered AS (
+			SELECT r.id, r.pvz_id, p.city, (r.created_at AT TIME ZONE p.timezone)::date AS day
+			FROM receptions r
+			JOIN pvz p ON p.id = r.pvz_id
+			WHERE ($1 = '' OR r.pvz_id = NULLIF($1, '')::uuid)
+				AND ($2 = '' OR p.city = $2)
+				AND ($3::date IS NULL OR (r.created_at >= $3::date - 2
+					AND r.created_at >= $3::date::timestamp AT TIME ZONE p.timezone))
+				AND ($4::date IS NULL OR (r.created_at < $4::date + 3
+					AND r.created_at < ($4::date + 1)::timestamp AT TIME ZONE p.timezone))
+		), days AS (
+			SELECT pvz_id, city, day, COUNT(*) AS receptions
+			FROM filtered
+			GROUP BY pvz_id, city, day
+		), types AS (
+			SELECT f.pvz_id, f.day, pr.type, COUNT(*) AS products
+			FROM filtered f
+			JOIN products pr ON pr.reception_id = f.id
+			GROUP BY f.pvz_id, f.day, pr.type
+		), conditions AS (
+			SELECT pvz_id, day, jsonb_object_agg(condition, products) AS by_condition,
+				COALESCE(SUM(products) FILTER (WHERE condition <> 'ok'), 0) AS damaged
+			FROM (
+				SELECT f.pvz_id, f.day, pr.condition, COUNT(*) AS products
+				FROM filtered f
+				JOIN products pr ON pr.reception_id = f.id
+				GROUP BY f.pvz_id, f.day, pr.condition
+			) c
+			GROUP BY pvz_id, day
+		)
+		SELECT d.pvz_id, d.city, d.day, d.receptions,
+			COALESCE(SUM(t.products), 0),
+			COALESCE(jsonb_object_agg(t.type, t.products) FILTER (WHERE t.type IS NOT NULL), '{}'),
+			COALESCE(c.by_condition, '{}'), COALESCE(c.damaged, 0)
+		FROM days d
+		LEFT JOIN types t ON t.pvz_id = d.pvz_id AND t.day = d.day
+		LEFT JOIN conditions c ON c.pvz_id = d.pvz_id AND c.day = d.day
+		GROUP BY d.pvz_id, d.city, d.day, d.receptions, c.by_condition, c.damaged
+		ORDER BY d.day DESC, d.city, d.pvz_id
+		LIMIT $5 OFFSET $6`,
+		filter.PvzID, filter.City, filter.From, filter.To, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []model.DailyReceptionStats{}
+	for rows.Next() {
+		var row model.DailyReceptionStats
+		var day time.Time
+		var damaged int
+
+		if err := rows.Scan(&row.PvzID, &row.City, &day, &row.Receptions, &row.Products, &row.ByType,
+			&row.ByCondition, &damaged); err != nil {
+			return nil, err
+		}
+
+		row.Date = day.Format(time.DateOnly)
+		if row.Products > 0 {
+			row.DamagedRate = float64(damaged) / float64(row.Products)
+		}
+		stats = append(stats, row)
+	}
+
+	return stats, rows.Err()
+}
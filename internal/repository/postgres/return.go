@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	returnBatchColumns   = "id,created_at,pvz_id,status,COALESCE(created_by::text, ''),closed_at"
+	productReturnColumns = "id,product_id,batch_id,reason,COALESCE(comment, ''),COALESCE(created_by::text, ''),created_at"
+)
+
+func scanReturnBatch(row pgx.Row) (*model.ReturnBatch, error) {
+	var batch model.ReturnBatch
+
+	err := row.Scan(&batch.ID, &batch.DateTime, &batch.PvzID, &batch.Status, &batch.CreatedBy, &batch.ClosedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+func scanProductReturn(row pgx.Row) (*model.ProductReturn, error) {
+	var productReturn model.ProductReturn
+
+	err := row.Scan(&productReturn.ID, &productReturn.ProductID, &productReturn.BatchID, &productReturn.Reason,
+		&productReturn.Comment, &productReturn.CreatedBy, &productReturn.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &productReturn, nil
+}
+
+// CreateReturnBatch открывает партию возвратов. Если на ПВЗ уже есть открытая партия,
+// срабатывает индекс unique_active_return_batch и возвращается nil.
+// Строка ПВЗ читается FOR SHARE, как в CreateReception: на неактивном ПВЗ партия не открывается
+func (p *Postgres) CreateReturnBatch(pvzID, createdBy string) (*model.ReturnBatch, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	batch, err := scanReturnBatch(conn.QueryRow(context.Background(),
+		`INSERT INTO return_batches (pvz_id, status, created_by)
+		SELECT id, $2, NULLIF($3, '')::uuid FROM pvz WHERE id = $1 AND status = $4 FOR SHARE
+		RETURNING `+returnBatchColumns,
+		pvzID, model.ReceptionInProgress, createdBy, model.PvzActive,
+	))
+	if isUniqueViolation(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, repository.ErrPvzNotActive
+	}
+
+	return batch, nil
+}
+
+func (p *Postgres) FindOpenReturnBatch(pvzID string) (*model.ReturnBatch, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanReturnBatch(conn.QueryRow(context.Background(),
+		"SELECT "+returnBatchColumns+" FROM return_batches WHERE pvz_id = $1 AND status = $2",
+		pvzID, model.ReceptionInProgress,
+	))
+}
+
+// CloseReturnBatch закрывает открытую партию ПВЗ, nil - открытой партии нет.
+// Партия без возвратов не закрывается: строка партии блокируется, чтобы возврат,
+// оформляемый параллельно, не проскочил между проверкой и закрытием
+func (p *Postgres) CloseReturnBatch(pvzID string) (*model.ReturnBatch, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	var (
+		batchID    string
+		hasReturns bool
+	)
+
+	err = tx.QueryRow(context.Background(),
+		`SELECT id, EXISTS (SELECT 1 FROM product_returns pr WHERE pr.batch_id = b.id)
+		FROM return_batches b WHERE pvz_id = $1 AND status = $2 FOR UPDATE`,
+		pvzID, model.ReceptionInProgress,
+	).Scan(&batchID, &hasReturns)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !hasReturns {
+		return nil, repository.ErrReturnBatchEmpty
+	}
+
+	batch, err := scanReturnBatch(tx.QueryRow(context.Background(),
+		"UPDATE return_batches SET status = $1, closed_at = NOW() WHERE id = $2 RETURNING "+returnBatchColumns,
+		model.ReceptionClose, batchID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// ReturnProduct переводит товар в returned и записывает возврат в открытую партию ПВЗ
+// одним запросом. nil - статус товара уже не подходит или партия успела закрыться.
+// Партия читается FOR SHARE: если её сейчас закрывает CloseReturnBatch, запрос дождётся
+// коммита и перепроверит статус, а не положит товар в уже закрытую партию
+func (p *Postgres) ReturnProduct(productID, pvzID string, productReturn *model.ProductReturn) (*model.ProductReturn, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanProductReturn(conn.QueryRow(context.Background(),
+		`WITH batch AS (
+			SELECT id FROM return_batches WHERE pvz_id = $2 AND status = $3 FOR SHARE
+		), updated AS (
+			UPDATE products SET status = $4, issue_code_hash = NULL, status_changed_at = NOW()
+			WHERE id = $1 AND status IN ($5, $6, $7) AND EXISTS (SELECT 1 FROM batch)
+			RETURNING id
+		)
+		INSERT INTO product_returns (product_id, batch_id, reason, comment, created_by)
+		SELECT u.id, b.id, $8, NULLIF($9, ''), NULLIF($10, '')::uuid FROM updated u, batch b
+		RETURNING `+productReturnColumns,
+		productID, pvzID, model.ReceptionInProgress,
+		model.ProductReturned, model.ProductReceived, model.ProductReady, model.ProductIssued,
+		productReturn.Reason, productReturn.Comment, productReturn.CreatedBy,
+	))
+}
+
+// ListReturnBatches возвращает партии возвратов ПВЗ из диапазона дат, новые первыми.
+// Даты включительные и считаются по местному времени ПВЗ
+func (p *Postgres) ListReturnBatches(pvzIDs []string, from, to *time.Time) ([]model.ReturnBatch, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+returnBatchColumns+` FROM return_batches
+		WHERE pvz_id = ANY($1::uuid[])
+			AND (($2::date IS NULL AND $3::date IS NULL)
+				OR (created_at AT TIME ZONE (SELECT p.timezone FROM pvz p WHERE p.id = pvz_id))::date
+					BETWEEN COALESCE($2::date, '-infinity') AND COALESCE($3::date, 'infinity'))
+		ORDER BY created_at DESC`,
+		pvzIDs, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []model.ReturnBatch{}
+	for rows.Next() {
+		batch, err := scanReturnBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		batches = append(batches, *batch)
+	}
+
+	return batches, rows.Err()
+}
+
+// ListReturnsByBatches возвращает возвраты партий в порядке добавления
+func (p *Postgres) ListReturnsByBatches(batchIDs []string) ([]model.ProductReturn, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		"SELECT "+productReturnColumns+" FROM product_returns WHERE batch_id = ANY($1::uuid[]) ORDER BY created_at",
+		batchIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returns := []model.ProductReturn{}
+	for rows.Next() {
+		productReturn, err := scanProductReturn(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		returns = append(returns, *productReturn)
+	}
+
+	return returns, rows.Err()
+}
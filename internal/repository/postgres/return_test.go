@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Возврат, начатый пока партия закрывается, не должен попасть в закрытую партию:
+// после коммита закрытия он видит, что открытой партии нет, и товар остаётся на ПВЗ
+func TestReturnProductRacesBatchClose(t *testing.T) {
+	db := newTestPostgres(t)
+	ctx := context.Background()
+
+	pvz, err := db.CreatePvz(&model.PVZ{City: "Москва", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+
+	_, err = db.CreateReception(pvz.ID)
+	require.NoError(t, err)
+
+	product, err := db.CreateProduct(pvz.ID, "", &model.Product{Type: model.ProductElectronics, Condition: model.ConditionOK}, time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, product)
+
+	batch, err := db.CreateReturnBatch(pvz.ID, "")
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+
+	// Закрытие держит блокировку строки партии, как CloseReturnBatch
+	tx, err := db.Pool.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "SELECT id FROM return_batches WHERE id = $1 FOR UPDATE", batch.ID)
+	require.NoError(t, err)
+
+	type result struct {
+		productReturn *model.ProductReturn
+		err           error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		productReturn, err := db.ReturnProduct(product.ID, pvz.ID, &model.ProductReturn{Reason: model.ReturnDamaged})
+		done <- result{productReturn, err}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("return did not wait for the batch being closed")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE return_batches SET status = $1, closed_at = NOW() WHERE id = $2",
+		model.ReceptionClose, batch.ID,
+	)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	res := <-done
+	assert.NoError(t, res.err)
+	assert.Nil(t, res.productReturn)
+
+	entry, err := db.FindProductWithReception(product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductReceived, entry.Status)
+}
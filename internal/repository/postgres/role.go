@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+const roleColumns = `r.name, r.description, r.builtin,
+	COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')`
+
+func scanRole(row pgx.Row) (*model.Role, error) {
+	var role model.Role
+	var permissions []string
+
+	if err := row.Scan(&role.Name, &role.Description, &role.Builtin, &permissions); err != nil {
+		return nil, err
+	}
+
+	role.Permissions = make([]model.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		role.Permissions = append(role.Permissions, model.Permission(permission))
+	}
+
+	return &role, nil
+}
+
+func (p *Postgres) FindRole(name model.UserRole) (*model.Role, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	role, err := scanRole(conn.QueryRow(context.Background(),
+		`SELECT `+roleColumns+`
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE r.name = $1
+		GROUP BY r.name`,
+		name,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return role, err
+}
+
+func (p *Postgres) ListRoles() ([]model.Role, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT `+roleColumns+`
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name
+		ORDER BY r.name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []model.Role{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+
+	return roles, rows.Err()
+}
+
+// CreateRole создаёт роль вместе с правами. Если роль с таким именем уже есть, возвращает nil
+func (p *Postgres) CreateRole(role *model.Role) (*model.Role, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	_, err = tx.Exec(context.Background(),
+		"INSERT INTO roles (name, description) VALUES ($1, $2)",
+		role.Name, role.Description,
+	)
+	if isUniqueViolation(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := insertRolePermissions(tx, role.Name, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return p.FindRole(role.Name)
+}
+
+// SetRolePermissions заменяет набор прав роли целиком
+func (p *Postgres) SetRolePermissions(name model.UserRole, permissions []model.Permission) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), "DELETE FROM role_permissions WHERE role = $1", name); err != nil {
+		return err
+	}
+
+	if err := insertRolePermissions(tx, name, permissions); err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func insertRolePermissions(tx pgx.Tx, name model.UserRole, permissions []model.Permission) error {
+	values := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		values = append(values, string(permission))
+	}
+
+	_, err := tx.Exec(context.Background(),
+		`INSERT INTO role_permissions (role, permission)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING`,
+		name, values,
+	)
+
+	return err
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+func (p *Postgres) CreateLoginAttempt(attempt *model.LoginAttempt) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		`INSERT INTO login_attempts (kind, user_id, email, role, ip, user_agent, success, reason)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, $7, $8)`,
+		attempt.Kind, attempt.UserID, attempt.Email, attempt.Role, attempt.IP, attempt.UserAgent, attempt.Success, attempt.Reason,
+	)
+
+	return err
+}
+
+func (p *Postgres) ListLoginAttempts(filter model.LoginAttemptFilter, limit, offset int) ([]model.LoginAttempt, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT id, kind, COALESCE(user_id::text, ''), email, role, ip, user_agent, success, reason, created_at
+		FROM login_attempts
+		WHERE ($1 = '' OR user_id = NULLIF($1, '')::uuid)
+			AND ($2 = '' OR email = $2)
+			AND ($3 = '' OR ip = $3)
+			AND ($4 = '' OR kind = $4)
+			AND ($5::boolean IS NULL OR success = $5)
+			AND ($6::timestamptz IS NULL OR created_at >= $6)
+			AND ($7::timestamptz IS NULL OR created_at < $7)
+		ORDER BY created_at DESC, id
+		LIMIT $8 OFFSET $9`,
+		filter.UserID, filter.Email, filter.IP, filter.Kind, filter.Success, filter.From, filter.To, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []model.LoginAttempt{}
+	for rows.Next() {
+		var attempt model.LoginAttempt
+		err := rows.Scan(&attempt.ID, &attempt.Kind, &attempt.UserID, &attempt.Email, &attempt.Role,
+			&attempt.IP, &attempt.UserAgent, &attempt.Success, &attempt.Reason, &attempt.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+func (p *Postgres) CreateSession(userID, ip, userAgent string, expiresAt time.Time) (*model.Session, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	session := model.Session{UserID: userID, IP: ip, UserAgent: userAgent, ExpiresAt: expiresAt}
+
+	err = conn.QueryRow(context.Background(),
+		`INSERT INTO sessions (user_id, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, refreshed_at`,
+		userID, ip, userAgent, expiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.RefreshedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// ExtendSession продлевает ещё не истёкшую сессию пользователя. false - сессии нет или она истекла
+func (p *Postgres) ExtendSession(id, userID, ip, userAgent string, expiresAt time.Time) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(),
+		`UPDATE sessions SET ip = $3, user_agent = $4, refreshed_at = NOW(), expires_at = $5
+		WHERE id = $1 AND user_id = $2 AND expires_at > NOW()`,
+		id, userID, ip, userAgent, expiresAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// SessionActive проверяет, что сессия пользователя существует и не истекла
+func (p *Postgres) SessionActive(id, userID string) (bool, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	var active bool
+
+	err = conn.QueryRow(context.Background(),
+		"SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1 AND user_id = $2 AND expires_at > NOW())",
+		id, userID,
+	).Scan(&active)
+
+	return active, err
+}
+
+func (p *Postgres) ListActiveSessions(userID string) ([]model.Session, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT id, user_id, ip, user_agent, created_at, refreshed_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY refreshed_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		var session model.Session
+		err := rows.Scan(&session.ID, &session.UserID, &session.IP, &session.UserAgent,
+			&session.CreatedAt, &session.RefreshedAt, &session.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
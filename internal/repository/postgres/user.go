@@ -4,22 +4,19 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
 
 	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
 	"github.com/jackc/pgx/v5"
 )
 
-func (p *Postgres) FindByEmail(email string) (*model.User, error) {
-	conn, err := p.Pool.Acquire(context.Background())
-	if err != nil {
-		log.Fatal("DB connect failed:", err)
-	}
-	defer conn.Release()
+// Удалённые пользователи (deleted_at IS NOT NULL) для приложения не существуют
+const userColumns = "id,email,password,role,disabled,created_at"
 
+func scanUser(row pgx.Row) (*model.User, error) {
 	var user model.User
 
-	err = conn.QueryRow(context.Background(), "SELECT id,email,password,role  FROM users WHERE email = $1 LIMIT 1", email).
-		Scan(&user.ID, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.Disabled, &user.CreatedAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -30,6 +27,28 @@ func (p *Postgres) FindByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
+func (p *Postgres) FindByEmail(email string) (*model.User, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanUser(conn.QueryRow(context.Background(),
+		"SELECT "+userColumns+" FROM users WHERE lower(email) = lower($1) AND deleted_at IS NULL LIMIT 1", email))
+}
+
+func (p *Postgres) FindByID(id string) (*model.User, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	return scanUser(conn.QueryRow(context.Background(),
+		"SELECT "+userColumns+" FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1", id))
+}
+
 func (p *Postgres) CreateUser(email, password string, role model.UserRole) (*model.User, error) {
 	conn, err := p.Pool.Acquire(context.Background())
 	if err != nil {
@@ -52,3 +71,133 @@ func (p *Postgres) CreateUser(email, password string, role model.UserRole) (*mod
 
 	return user, nil
 }
+
+func (p *Postgres) UpdatePassword(userID, password string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE users SET password = $1 WHERE id = $2",
+		password, userID,
+	)
+
+	return err
+}
+
+// likeEscaper экранирует спецсимволы LIKE, чтобы строка поиска сравнивалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListUsers ищет пользователей по подстроке почты, пустая строка - без фильтра
+func (p *Postgres) ListUsers(email string, limit, offset int) ([]model.User, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT `+userColumns+` FROM users
+		WHERE deleted_at IS NULL AND ($1 = '' OR email ILIKE '%' || $1 || '%' ESCAPE '\')
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3`,
+		likeEscaper.Replace(email), limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+func (p *Postgres) UpdateUserRole(id string, role model.UserRole) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE users SET role = $1 WHERE id = $2 AND deleted_at IS NULL",
+		role, id,
+	)
+
+	return err
+}
+
+func (p *Postgres) SetUserDisabled(id string, disabled bool) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE users SET disabled = $1 WHERE id = $2 AND deleted_at IS NULL",
+		disabled, id,
+	)
+
+	return err
+}
+
+func (p *Postgres) SoftDeleteUser(id string) error {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(),
+		"UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL",
+		id,
+	)
+
+	return err
+}
+
+// FindEmailDuplicates ищет активных пользователей, чьи почты совпадают без учёта регистра и пробелов
+func (p *Postgres) FindEmailDuplicates() ([]model.EmailDuplicate, error) {
+	conn, err := p.Pool.Acquire(context.Background())
+	if err != nil {
+		log.Fatal("DB connect failed:", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(),
+		`SELECT lower(btrim(email)) AS normalized,
+			array_agg(id::text ORDER BY created_at),
+			array_agg(email ORDER BY created_at)
+		FROM users
+		WHERE deleted_at IS NULL
+		GROUP BY normalized
+		HAVING COUNT(*) > 1
+		ORDER BY normalized`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := []model.EmailDuplicate{}
+	for rows.Next() {
+		var duplicate model.EmailDuplicate
+		if err := rows.Scan(&duplicate.Normalized, &duplicate.UserIDs, &duplicate.Emails); err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, rows.Err()
+}
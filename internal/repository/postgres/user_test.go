@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikeEscaper_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name     string
+		search   string
+		expected string
+	}{
+		{name: "plain", search: "ivan@example.com", expected: "ivan@example.com"},
+		{name: "underscore", search: "_", expected: `\_`},
+		{name: "percent", search: "100%", expected: `100\%`},
+		{name: "backslash", search: `a\b`, expected: `a\\b`},
+		{name: "mixed", search: `first_last%\`, expected: `first\_last\%\\`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, likeEscaper.Replace(tc.search))
+		})
+	}
+}
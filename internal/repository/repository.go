@@ -1,8 +1,117 @@
 package repository
 
-import "github.com/et0/avito-tech-internship-spring-2025/internal/model"
+import (
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
 
 type Database interface {
 	FindByEmail(email string) (*model.User, error)
+	FindByID(id string) (*model.User, error)
 	CreateUser(email, password string, role model.UserRole) (*model.User, error)
+	UpdatePassword(userID, password string) error
+	ListUsers(email string, limit, offset int) ([]model.User, error)
+	UpdateUserRole(id string, role model.UserRole) error
+	SetUserDisabled(id string, disabled bool) error
+	SoftDeleteUser(id string) error
+	CountUsersByRole(role model.UserRole) (int, error)
+	FindEmailDuplicates() ([]model.EmailDuplicate, error)
+
+	CreateInvitation(invitation *model.Invitation, codeHash string) (*model.Invitation, error)
+	CreateUserWithInvitation(email, password string, role model.UserRole, codeHash string) (*model.User, error)
+
+	FindRole(name model.UserRole) (*model.Role, error)
+	ListRoles() ([]model.Role, error)
+	CreateRole(role *model.Role) (*model.Role, error)
+	SetRolePermissions(name model.UserRole, permissions []model.Permission) error
+
+	PvzExists(id string) (bool, error)
+	CreatePvz(pvz *model.PVZ) (*model.PVZ, error)
+	ListPvz(filter model.PvzFilter, limit, offset int) ([]model.PVZ, error)
+	FindPvz(id string) (*model.PVZ, error)
+	UpdatePvz(pvz *model.PVZ) (*model.PVZ, error)
+	NearbyPvz(center model.GeoPoint, radius float64, limit, offset int) ([]model.NearbyPvz, error)
+	ChangePvzStatus(pvzID string, from []model.PvzStatus, to model.PvzStatus, reason, changedBy string) (*model.PVZ, error)
+	SetPvzCapacity(pvzID string, capacity model.PvzCapacity) error
+	ListPvzCapacities(pvzIDs []string) ([]model.PvzTypeCount, error)
+	ListPvzUsage(pvzIDs []string) ([]model.PvzTypeCount, error)
+	ListPvzSchedules(pvzIDs []string, since *time.Time) ([]model.PvzSchedule, error)
+	SetPvzSchedule(schedule model.PvzSchedule) error
+
+	ListCities(activeOnly bool) ([]model.City, error)
+	FindCity(id string) (*model.City, error)
+	FindCityByName(name string) (*model.City, error)
+	CreateCity(name string) (*model.City, error)
+	RenameCity(id, name, changedBy string) (*model.City, error)
+	SetCityActive(id string, active bool) error
+	ListCityRenames(id string) ([]model.CityRename, error)
+
+	CreateReception(pvzID string) (*model.Reception, error)
+	FindOpenReception(pvzID string) (*model.Reception, error)
+	CloseReception(pvzID string) (*model.Reception, error)
+	FindLastReception(pvzID string) (*model.Reception, error)
+	ReopenReception(id string, closedAfter time.Time, actorID, reason string) (*model.Reception, error)
+	ListReceptionEvents(receptionID string) ([]model.ReceptionEvent, error)
+	ProcessStaleReceptions(before time.Time, action model.ReceptionAction, reason string) ([]model.Reception, bool, error)
+	ListReceptions(pvzIDs []string, from, to *time.Time) ([]model.Reception, error)
+
+	ListProductTypes(activeOnly bool) ([]model.ProductTypeInfo, error)
+	FindProductType(code model.ProductType) (*model.ProductTypeInfo, error)
+	CreateProductType(productType *model.ProductTypeInfo) (*model.ProductTypeInfo, error)
+	UpdateProductTypeNames(code model.ProductType, names map[string]string) error
+	SetProductTypeActive(code model.ProductType, active bool) error
+
+	CreateProduct(pvzID, userID string, product *model.Product, barcodeSince time.Time) (*model.Product, error)
+	DeleteLastProduct(pvzID string) (*model.Product, error)
+	ListProductsByBarcode(barcode string) ([]model.ProductHistoryEntry, error)
+	FindProductWithReception(id string) (*model.ProductHistoryEntry, error)
+	ListProducts(pvzID string, status model.ProductStatus, limit, offset int) ([]model.Product, error)
+	ListProductsByReceptions(receptionIDs []string, status model.ProductStatus) ([]model.Product, error)
+	MarkProductReady(id, codeHash string) (*model.Product, error)
+	IssueProduct(id, codeHash, issuedBy string, maxAttempts int) (*model.Product, error)
+
+	CreateCell(cell *model.StorageCell) (*model.StorageCell, error)
+	ListCells(pvzID string) ([]model.StorageCell, error)
+	FindCell(id string) (*model.StorageCell, error)
+	FindCellByCode(pvzID, code string) (*model.StorageCell, error)
+	UpdateCellCapacity(id string, capacity int) error
+	PlaceProduct(productID, cellID, movedBy string) error
+	ListCellMoves(productID string) ([]model.CellMove, error)
+
+	CreateReturnBatch(pvzID, createdBy string) (*model.ReturnBatch, error)
+	FindOpenReturnBatch(pvzID string) (*model.ReturnBatch, error)
+	CloseReturnBatch(pvzID string) (*model.ReturnBatch, error)
+	ReturnProduct(productID, pvzID string, productReturn *model.ProductReturn) (*model.ProductReturn, error)
+	ListReturnBatches(pvzIDs []string, from, to *time.Time) ([]model.ReturnBatch, error)
+	ListReturnsByBatches(batchIDs []string) ([]model.ProductReturn, error)
+
+	DailyReceptionStats(filter model.ReportFilter, limit, offset int) ([]model.DailyReceptionStats, error)
+
+	CreateAssignment(userID, pvzID, createdBy string) error
+	DeleteAssignment(userID, pvzID string) (bool, error)
+	IsAssigned(userID, pvzID string) (bool, error)
+	ListAssignments(userID, pvzID string) ([]model.Assignment, error)
+
+	CreateAPIKey(key *model.APIKey) (*model.APIKey, error)
+	FindAPIKeyByPrefix(prefix string) (*model.APIKey, error)
+	ListAPIKeys() ([]model.APIKey, error)
+	RevokeAPIKey(id string) (bool, error)
+	TouchAPIKey(id string) error
+
+	FindLoginThrottle(kind model.ThrottleKind, value string) (*model.LoginThrottle, error)
+	RegisterLoginFailure(kind model.ThrottleKind, value string, now time.Time, staleBefore *time.Time) (*model.LoginThrottle, error)
+	LockLoginThrottle(kind model.ThrottleKind, value string, until time.Time) error
+	DeleteLoginThrottle(kind model.ThrottleKind, value string) error
+
+	CreateLoginAttempt(attempt *model.LoginAttempt) error
+	ListLoginAttempts(filter model.LoginAttemptFilter, limit, offset int) ([]model.LoginAttempt, error)
+	CreateSession(userID, ip, userAgent string, expiresAt time.Time) (*model.Session, error)
+	ExtendSession(id, userID, ip, userAgent string, expiresAt time.Time) (bool, error)
+	ListActiveSessions(userID string) ([]model.Session, error)
+	SessionActive(id, userID string) (bool, error)
+
+	CreatePasswordResetToken(userID, tokenHash string, expiresAt time.Time) error
+	ResetPassword(tokenHash, password string) (string, error)
+	ChangePassword(userID, password string) error
 }
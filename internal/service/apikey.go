@@ -0,0 +1,164 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const (
+	apiKeyPrefixLength = 4 // байт, в ключе это 8 hex-символов
+	apiKeySecretLength = 32
+)
+
+var (
+	ErrInvalidAPIKey   = errors.New("invalid api key")
+	ErrInvalidScope    = errors.New("invalid scope")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrExpiresInPast   = errors.New("expiration must be in the future")
+	ErrInactiveKeyUser = errors.New("api key user is disabled")
+)
+
+type APIKeyService interface {
+	Create(actorID, name, userID string, scopes []model.Scope, expiresAt *time.Time) (*model.APIKey, string, error)
+	List() ([]model.APIKey, error)
+	Revoke(id string) error
+	VerifyAPIKey(key string) (*model.TokenClaims, error)
+}
+
+type apiKeyService struct {
+	db repository.Database
+}
+
+func NewAPIKeyService(db repository.Database) *apiKeyService {
+	return &apiKeyService{db}
+}
+
+// Create выпускает ключ вида pvz_<prefix>_<secret>. Полный ключ возвращается только здесь
+func (s *apiKeyService) Create(actorID, name, userID string, scopes []model.Scope, expiresAt *time.Time) (*model.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidScope
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(model.Scopes, scope) {
+			return nil, "", ErrInvalidScope
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrExpiresInPast
+	}
+
+	user, err := s.db.FindByID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if user == nil {
+		return nil, "", ErrUserNotFound
+	}
+
+	if user.Disabled {
+		return nil, "", ErrInactiveKeyUser
+	}
+
+	prefixBytes := make([]byte, apiKeyPrefixLength)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key")
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+
+	secret, err := newSecret(apiKeySecretLength)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api key")
+	}
+
+	raw := model.APIKeyPrefix + prefix + "_" + secret
+
+	key, err := s.db.CreateAPIKey(&model.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashSecret(raw),
+		UserID:    userID,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedBy: actorID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, raw, nil
+}
+
+func (s *apiKeyService) List() ([]model.APIKey, error) {
+	return s.db.ListAPIKeys()
+}
+
+func (s *apiKeyService) Revoke(id string) error {
+	revoked, err := s.db.RevokeAPIKey(id)
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// VerifyAPIKey проверяет ключ и возвращает данные пользователя, от имени которого он действует
+func (s *apiKeyService) VerifyAPIKey(raw string) (*model.TokenClaims, error) {
+	rest, found := strings.CutPrefix(raw, model.APIKeyPrefix)
+	if !found {
+		return nil, ErrInvalidAPIKey
+	}
+
+	prefix, _, found := strings.Cut(rest, "_")
+	if !found || prefix == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.db.FindAPIKeyByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashSecret(raw))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.db.FindByID(key.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.Disabled {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := s.db.TouchAPIKey(key.ID); err != nil {
+		return nil, err
+	}
+
+	return &model.TokenClaims{
+		UserID:   user.ID,
+		Role:     user.Role,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
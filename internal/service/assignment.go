@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+var (
+	ErrNotEmployee        = errors.New("only users who can open receptions can be assigned to pvz")
+	ErrAssignmentNotFound = errors.New("assignment not found")
+)
+
+type AssignmentService interface {
+	Assign(actorID, userID, pvzID string) error
+	Unassign(userID, pvzID string) error
+	ListByPvz(pvzID string) ([]model.Assignment, error)
+	ListByUser(userID string) ([]model.Assignment, error)
+}
+
+type assignmentService struct {
+	db repository.Database
+}
+
+func NewAssignmentService(db repository.Database) *assignmentService {
+	return &assignmentService{db}
+}
+
+func (s *assignmentService) Assign(actorID, userID, pvzID string) error {
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrPvzNotFound
+	}
+
+	user, err := s.db.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	// Назначать имеет смысл только роли, которым разрешено открывать приёмки
+	role, err := s.db.FindRole(user.Role)
+	if err != nil {
+		return err
+	}
+
+	if role == nil || !slices.Contains(role.Permissions, model.PermReceptionOpen) {
+		return ErrNotEmployee
+	}
+
+	return s.db.CreateAssignment(userID, pvzID, actorID)
+}
+
+func (s *assignmentService) Unassign(userID, pvzID string) error {
+	deleted, err := s.db.DeleteAssignment(userID, pvzID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrAssignmentNotFound
+	}
+
+	return nil
+}
+
+func (s *assignmentService) ListByPvz(pvzID string) ([]model.Assignment, error) {
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrPvzNotFound
+	}
+
+	return s.db.ListAssignments("", pvzID)
+}
+
+func (s *assignmentService) ListByUser(userID string) ([]model.Assignment, error) {
+	user, err := s.db.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return s.db.ListAssignments(userID, "")
+}
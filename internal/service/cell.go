@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const maxCellCapacity = 1000
+
+var (
+	ErrCellNotFound        = errors.New("storage cell not found")
+	ErrCellExists          = errors.New("storage cell already exists")
+	ErrInvalidCellCode     = errors.New("invalid storage cell code")
+	ErrInvalidCellCapacity = errors.New("invalid storage cell capacity")
+	ErrCellFull            = errors.New("storage cell is full")
+	ErrCapacityBelowUsage  = errors.New("capacity is below current occupancy")
+)
+
+// Код ячейки вроде A-12 или 3-04-2: латиница, цифры и дефисы
+var cellCodePattern = regexp.MustCompile(`^[0-9A-Z][0-9A-Z-]{0,15}$`)
+
+// CellService - ячейки хранения ПВЗ и размещение в них товаров
+type CellService interface {
+	List(pvzID string) ([]model.StorageCell, error)
+	Create(pvzID, code string, capacity int) (*model.StorageCell, error)
+	UpdateCapacity(pvzID, cellID string, capacity int) (*model.StorageCell, error)
+	Move(userID, productID, cellCode string) (*model.ProductLocation, error)
+	Locate(productID string) (*model.ProductLocation, error)
+}
+
+type cellService struct {
+	db repository.Database
+}
+
+func NewCellService(db repository.Database) *cellService {
+	return &cellService{db}
+}
+
+func (s *cellService) List(pvzID string) ([]model.StorageCell, error) {
+	if err := s.pvzExists(pvzID); err != nil {
+		return nil, err
+	}
+
+	return s.db.ListCells(pvzID)
+}
+
+func (s *cellService) Create(pvzID, code string, capacity int) (*model.StorageCell, error) {
+	code = model.NormalizeCellCode(code)
+
+	if !cellCodePattern.MatchString(code) {
+		return nil, ErrInvalidCellCode
+	}
+
+	if capacity < 1 || capacity > maxCellCapacity {
+		return nil, ErrInvalidCellCapacity
+	}
+
+	if err := s.pvzExists(pvzID); err != nil {
+		return nil, err
+	}
+
+	cell, err := s.db.CreateCell(&model.StorageCell{PvzID: pvzID, Code: code, Capacity: capacity})
+	if err != nil {
+		return nil, err
+	}
+
+	if cell == nil {
+		return nil, ErrCellExists
+	}
+
+	return cell, nil
+}
+
+// UpdateCapacity меняет вместимость. Уменьшить её ниже числа лежащих в ячейке товаров нельзя
+func (s *cellService) UpdateCapacity(pvzID, cellID string, capacity int) (*model.StorageCell, error) {
+	if capacity < 1 || capacity > maxCellCapacity {
+		return nil, ErrInvalidCellCapacity
+	}
+
+	cell, err := s.db.FindCell(cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cell == nil || cell.PvzID != pvzID {
+		return nil, ErrCellNotFound
+	}
+
+	err = s.db.UpdateCellCapacity(cellID, capacity)
+	if errors.Is(err, repository.ErrCellCapacityBelowUsage) {
+		return nil, ErrCapacityBelowUsage
+	} else if err != nil {
+		return nil, err
+	}
+
+	cell.Capacity = capacity
+
+	return cell, nil
+}
+
+// Move кладёт товар в ячейку его ПВЗ: и первое размещение, и перенос между ячейками.
+// Выданные и возвращённые товары на ПВЗ уже не лежат, их перемещать нельзя
+func (s *cellService) Move(userID, productID, cellCode string) (*model.ProductLocation, error) {
+	entry, err := s.db.FindProductWithReception(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		return nil, ErrProductNotFound
+	}
+
+	if err := authorizeEmployee(s.db, userID, entry.PvzID); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(model.ShelfProductStatuses, entry.Status) {
+		return nil, ErrProductStatusTransition
+	}
+
+	cell, err := s.db.FindCellByCode(entry.PvzID, model.NormalizeCellCode(cellCode))
+	if err != nil {
+		return nil, err
+	}
+
+	if cell == nil {
+		return nil, ErrCellNotFound
+	}
+
+	err = s.db.PlaceProduct(productID, cell.ID, userID)
+	if errors.Is(err, repository.ErrCellFull) {
+		return nil, ErrCellFull
+	} else if err != nil {
+		return nil, err
+	}
+
+	return s.Locate(productID)
+}
+
+// Locate отвечает, где товар: ПВЗ, текущая ячейка и все перемещения
+func (s *cellService) Locate(productID string) (*model.ProductLocation, error) {
+	entry, err := s.db.FindProductWithReception(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		return nil, ErrProductNotFound
+	}
+
+	location := &model.ProductLocation{Product: entry.Product, PvzID: entry.PvzID}
+
+	if entry.CellID != "" {
+		if location.Cell, err = s.db.FindCell(entry.CellID); err != nil {
+			return nil, err
+		}
+	}
+
+	if location.Moves, err = s.db.ListCellMoves(productID); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+func (s *cellService) pvzExists(pvzID string) error {
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrPvzNotFound
+	}
+
+	return nil
+}
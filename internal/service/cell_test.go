@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPvzID  = "f2d5b4a7-3c1e-4b9a-8f6d-2e7c9a1b3d5f"
+	testCellID = "7a9c1e3b-5d7f-4a2c-9e4b-6d8f0a2c4e6b"
+)
+
+func TestUpdateCellCapacity_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pvzID          string
+		capacity       int
+		updateErr      error
+		expectedErr    error
+		expectedUpdate bool
+	}{
+		{name: "invalid_capacity", pvzID: testPvzID, capacity: 0, expectedErr: ErrInvalidCellCapacity},
+		{name: "cell_of_other_pvz", pvzID: "0b2d4f6a-8c0e-4a2c-b4d6-f8a0c2e4a6c8", capacity: 5, expectedErr: ErrCellNotFound},
+		{
+			// Занятость проверяется в хранилище под блокировкой ячейки, а не по прочитанному заранее значению
+			name:           "below_occupancy",
+			pvzID:          testPvzID,
+			capacity:       2,
+			updateErr:      repository.ErrCellCapacityBelowUsage,
+			expectedErr:    ErrCapacityBelowUsage,
+			expectedUpdate: true,
+		},
+		{name: "updated", pvzID: testPvzID, capacity: 5, expectedUpdate: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &cellDB{
+				cell:      &model.StorageCell{ID: testCellID, PvzID: testPvzID, Code: "A-1", Capacity: 3},
+				updateErr: tc.updateErr,
+			}
+
+			cell, err := NewCellService(db).UpdateCapacity(tc.pvzID, testCellID, tc.capacity)
+
+			assert.Equal(t, tc.expectedUpdate, db.updated)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, cell)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.capacity, cell.Capacity)
+		})
+	}
+}
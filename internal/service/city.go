@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const maxCityNameLength = 100
+
+var (
+	ErrCityNotFound    = errors.New("city not found")
+	ErrCityExists      = errors.New("city already exists")
+	ErrInvalidCityName = errors.New("invalid city name")
+)
+
+type CityService interface {
+	List(activeOnly bool) ([]model.City, error)
+	Create(name string) (*model.City, error)
+	Rename(actorID, id, name string) (*model.City, error)
+	SetActive(id string, active bool) (*model.City, error)
+	History(id string) ([]model.CityRename, error)
+}
+
+type cityService struct {
+	db repository.Database
+}
+
+func NewCityService(db repository.Database) *cityService {
+	return &cityService{db}
+}
+
+func (s *cityService) List(activeOnly bool) ([]model.City, error) {
+	return s.db.ListCities(activeOnly)
+}
+
+func (s *cityService) Create(name string) (*model.City, error) {
+	name, err := normalizeCityName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	city, err := s.db.CreateCity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if city == nil {
+		return nil, ErrCityExists
+	}
+
+	return city, nil
+}
+
+// Rename меняет название города. ПВЗ в этом городе получают новое название автоматически
+func (s *cityService) Rename(actorID, id, name string) (*model.City, error) {
+	name, err := normalizeCityName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	city, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if city.Name == name {
+		return city, nil
+	}
+
+	renamed, err := s.db.RenameCity(id, name, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if renamed == nil {
+		return nil, ErrCityExists
+	}
+
+	return renamed, nil
+}
+
+// SetActive включает или выключает город. Существующие ПВЗ в выключенном городе продолжают работать,
+// запрещается только заводить новые
+func (s *cityService) SetActive(id string, active bool) (*model.City, error) {
+	if _, err := s.get(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetCityActive(id, active); err != nil {
+		return nil, err
+	}
+
+	return s.get(id)
+}
+
+func (s *cityService) History(id string) ([]model.CityRename, error) {
+	if _, err := s.get(id); err != nil {
+		return nil, err
+	}
+
+	return s.db.ListCityRenames(id)
+}
+
+func (s *cityService) get(id string) (*model.City, error) {
+	city, err := s.db.FindCity(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if city == nil {
+		return nil, ErrCityNotFound
+	}
+
+	return city, nil
+}
+
+func normalizeCityName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+
+	if name == "" || utf8.RuneCountInString(name) > maxCityNameLength {
+		return "", ErrInvalidCityName
+	}
+
+	return name, nil
+}
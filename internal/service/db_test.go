@@ -0,0 +1,57 @@
+package service
+
+import (
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+// fakeDB подменяет хранилище в тестах сервисов. Методы, которые тест не задал, паникуют
+// через встроенный nil-интерфейс, поэтому лишние обращения к базе сразу видны
+type fakeDB struct {
+	repository.Database
+
+	pvzExists bool
+	assigned  bool
+}
+
+func (f *fakeDB) PvzExists(pvzID string) (bool, error) {
+	return f.pvzExists, nil
+}
+
+func (f *fakeDB) IsAssigned(userID, pvzID string) (bool, error) {
+	return f.assigned, nil
+}
+
+// staleDB запоминает аргументы ProcessStaleReceptions
+type staleDB struct {
+	fakeDB
+
+	before time.Time
+	action model.ReceptionAction
+	reason string
+}
+
+func (f *staleDB) ProcessStaleReceptions(before time.Time, action model.ReceptionAction, reason string) ([]model.Reception, bool, error) {
+	f.before, f.action, f.reason = before, action, reason
+	return nil, true, nil
+}
+
+// cellDB отдаёт одну ячейку и ошибку обновления вместимости, которую вернуло бы хранилище
+type cellDB struct {
+	fakeDB
+
+	cell      *model.StorageCell
+	updateErr error
+	updated   bool
+}
+
+func (f *cellDB) FindCell(id string) (*model.StorageCell, error) {
+	return f.cell, nil
+}
+
+func (f *cellDB) UpdateCellCapacity(id string, capacity int) error {
+	f.updated = true
+	return f.updateErr
+}
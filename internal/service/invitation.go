@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const (
+	invitationCodeLength = 16
+	defaultInvitationTTL = 72 * time.Hour
+)
+
+var (
+	ErrInvitationRequired = errors.New("invitation is required for this role")
+	ErrInvalidInvitation  = errors.New("invalid or expired invitation")
+	ErrModeratorExists    = errors.New("moderator already exists")
+)
+
+type InvitationService interface {
+	Create(actorID string, email string, role model.UserRole) (*model.Invitation, string, error)
+}
+
+type invitationService struct {
+	db  repository.Database
+	ttl time.Duration
+}
+
+func NewInvitationService(db repository.Database, ttl time.Duration) *invitationService {
+	if ttl <= 0 {
+		ttl = defaultInvitationTTL
+	}
+
+	return &invitationService{db, ttl}
+}
+
+// Create возвращает приглашение и код к нему. Код больше нигде не сохраняется
+func (s *invitationService) Create(actorID string, email string, role model.UserRole) (*model.Invitation, string, error) {
+	email = model.NormalizeEmail(email)
+
+	if err := roleExists(s.db, role); err != nil {
+		return nil, "", err
+	}
+
+	code, err := newSecret(invitationCodeLength)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate invitation code")
+	}
+
+	invitation, err := s.db.CreateInvitation(&model.Invitation{
+		Email:     email,
+		Role:      role,
+		CreatedBy: actorID,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}, hashSecret(code))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return invitation, code, nil
+}
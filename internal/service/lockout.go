@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+// LoginLockedError возвращается, когда попытки входа временно заблокированы
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many login attempts, retry after %s", e.RetryAfter)
+}
+
+// Seconds округляет время ожидания вверх для заголовка Retry-After
+func (e *LoginLockedError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// checkLoginThrottle проверяет блокировки по почте и по IP и возвращает LoginLockedError,
+// если хотя бы одна из них ещё действует
+func (uS *userService) checkLoginThrottle(email, ip string) error {
+	var wait time.Duration
+
+	for kind, value := range map[model.ThrottleKind]string{model.ThrottleEmail: email, model.ThrottleIP: ip} {
+		if value == "" {
+			continue
+		}
+
+		throttle, err := uS.db.FindLoginThrottle(kind, value)
+		if err != nil {
+			return err
+		}
+
+		wait = max(wait, uS.retryAfter(throttle, time.Now()))
+	}
+
+	if wait > 0 {
+		return &LoginLockedError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// retryAfter вычисляет, сколько ещё нужно ждать до следующей попытки
+func (uS *userService) retryAfter(throttle *model.LoginThrottle, now time.Time) time.Duration {
+	if throttle == nil || uS.isStale(throttle, now) {
+		return 0
+	}
+
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now)
+	}
+
+	next := throttle.LastFailureAt.Add(uS.backoff(throttle.Failures))
+	if now.Before(next) {
+		return next.Sub(now)
+	}
+
+	return 0
+}
+
+// backoff растёт экспоненциально: base, 2*base, 4*base ... но не больше max_delay
+func (uS *userService) backoff(failures int) time.Duration {
+	if failures <= 0 || uS.lockout.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := uS.lockout.BaseDelay << min(failures-1, 30)
+	if delay <= 0 || (uS.lockout.MaxDelay > 0 && delay > uS.lockout.MaxDelay) {
+		delay = uS.lockout.MaxDelay
+	}
+
+	return delay
+}
+
+func (uS *userService) isStale(throttle *model.LoginThrottle, now time.Time) bool {
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return false
+	}
+
+	return uS.lockout.ResetAfter > 0 && now.Sub(throttle.LastFailureAt) > uS.lockout.ResetAfter
+}
+
+// registerLoginFailure увеличивает счётчики неудачных попыток и ставит блокировку при превышении лимита.
+// Инкремент выполняется в базе, решение о блокировке принимается по вернувшемуся значению
+func (uS *userService) registerLoginFailure(email, ip string) error {
+	limits := map[model.ThrottleKind]int{
+		model.ThrottleEmail: uS.lockout.MaxFailures,
+		model.ThrottleIP:    uS.lockout.MaxFailuresPerIP,
+	}
+
+	for kind, value := range map[model.ThrottleKind]string{model.ThrottleEmail: email, model.ThrottleIP: ip} {
+		if value == "" {
+			continue
+		}
+
+		now := time.Now()
+
+		var staleBefore *time.Time
+		if uS.lockout.ResetAfter > 0 {
+			before := now.Add(-uS.lockout.ResetAfter)
+			staleBefore = &before
+		}
+
+		throttle, err := uS.db.RegisterLoginFailure(kind, value, now, staleBefore)
+		if err != nil {
+			return err
+		}
+
+		if limits[kind] > 0 && throttle.Failures >= limits[kind] {
+			if err := uS.db.LockLoginThrottle(kind, value, now.Add(uS.lockout.LockoutDuration)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uS *userService) Unlock(email, ip string) error {
+	email = model.NormalizeEmail(email)
+
+	if email != "" {
+		if err := uS.db.DeleteLoginThrottle(model.ThrottleEmail, email); err != nil {
+			return err
+		}
+	}
+
+	if ip != "" {
+		if err := uS.db.DeleteLoginThrottle(model.ThrottleIP, ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testLockout = config.Lockout{
+	MaxFailures:     5,
+	LockoutDuration: 15 * time.Minute,
+	BaseDelay:       time.Second,
+	MaxDelay:        30 * time.Second,
+	ResetAfter:      time.Hour,
+}
+
+func TestBackoff_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name     string
+		lockout  config.Lockout
+		failures int
+		expected time.Duration
+	}{
+		{name: "no_failures", lockout: testLockout, failures: 0, expected: 0},
+		{name: "first_failure", lockout: testLockout, failures: 1, expected: time.Second},
+		{name: "doubles", lockout: testLockout, failures: 4, expected: 8 * time.Second},
+		{name: "capped_by_max_delay", lockout: testLockout, failures: 10, expected: 30 * time.Second},
+		{name: "huge_failures_do_not_overflow", lockout: testLockout, failures: 1000, expected: 30 * time.Second},
+		{name: "disabled_without_base_delay", lockout: config.Lockout{MaxDelay: time.Minute}, failures: 3, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uS := &userService{lockout: tc.lockout}
+
+			assert.Equal(t, tc.expected, uS.backoff(tc.failures))
+		})
+	}
+}
+
+func TestIsStale_TableDriven(t *testing.T) {
+	now := time.Now()
+	lockedUntil := now.Add(time.Minute)
+	expiredLock := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name     string
+		lockout  config.Lockout
+		throttle model.LoginThrottle
+		expected bool
+	}{
+		{
+			name:     "recent_failure",
+			lockout:  testLockout,
+			throttle: model.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-time.Minute)},
+			expected: false,
+		},
+		{
+			name:     "old_failure",
+			lockout:  testLockout,
+			throttle: model.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-2 * time.Hour)},
+			expected: true,
+		},
+		{
+			name:     "active_lock_is_never_stale",
+			lockout:  testLockout,
+			throttle: model.LoginThrottle{Failures: 5, LastFailureAt: now.Add(-2 * time.Hour), LockedUntil: &lockedUntil},
+			expected: false,
+		},
+		{
+			name:     "expired_lock",
+			lockout:  testLockout,
+			throttle: model.LoginThrottle{Failures: 5, LastFailureAt: now.Add(-2 * time.Hour), LockedUntil: &expiredLock},
+			expected: true,
+		},
+		{
+			name:     "reset_disabled",
+			lockout:  config.Lockout{BaseDelay: time.Second},
+			throttle: model.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-24 * time.Hour)},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uS := &userService{lockout: tc.lockout}
+
+			assert.Equal(t, tc.expected, uS.isStale(&tc.throttle, now))
+		})
+	}
+}
+
+func TestRetryAfter_TableDriven(t *testing.T) {
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+
+	testCases := []struct {
+		name     string
+		throttle *model.LoginThrottle
+		expected time.Duration
+	}{
+		{name: "no_throttle", throttle: nil, expected: 0},
+		{
+			name:     "locked",
+			throttle: &model.LoginThrottle{Failures: 5, LastFailureAt: now, LockedUntil: &lockedUntil},
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "inside_backoff",
+			throttle: &model.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-time.Second)},
+			expected: 3 * time.Second,
+		},
+		{
+			name:     "backoff_passed",
+			throttle: &model.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-5 * time.Second)},
+			expected: 0,
+		},
+		{
+			name:     "stale",
+			throttle: &model.LoginThrottle{Failures: 20, LastFailureAt: now.Add(-2 * time.Hour)},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uS := &userService{lockout: testLockout}
+
+			assert.Equal(t, tc.expected, uS.retryAfter(tc.throttle, now))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(actorID, name, userID string, scopes []model.Scope, expiresAt *time.Time) (*model.APIKey, string, error) {
+	args := m.Called(actorID, name, userID, scopes, expiresAt)
+	if key := args.Get(0); key != nil {
+		return key.(*model.APIKey), args.String(1), args.Error(2)
+	}
+	return nil, args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) List() ([]model.APIKey, error) {
+	args := m.Called()
+	if keys := args.Get(0); keys != nil {
+		return keys.([]model.APIKey), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) VerifyAPIKey(key string) (*model.TokenClaims, error) {
+	args := m.Called(key)
+	if claims := args.Get(0); claims != nil {
+		return claims.(*model.TokenClaims), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) Assign(actorID, userID, pvzID string) error {
+	args := m.Called(actorID, userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) Unassign(userID, pvzID string) error {
+	args := m.Called(userID, pvzID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) ListByPvz(pvzID string) ([]model.Assignment, error) {
+	args := m.Called(pvzID)
+	if assignments := args.Get(0); assignments != nil {
+		return assignments.([]model.Assignment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAssignmentService) ListByUser(userID string) ([]model.Assignment, error) {
+	args := m.Called(userID)
+	if assignments := args.Get(0); assignments != nil {
+		return assignments.([]model.Assignment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
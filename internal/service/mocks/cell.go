@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCellService struct {
+	mock.Mock
+}
+
+func (m *MockCellService) List(pvzID string) ([]model.StorageCell, error) {
+	args := m.Called(pvzID)
+	if cells := args.Get(0); cells != nil {
+		return cells.([]model.StorageCell), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCellService) Create(pvzID, code string, capacity int) (*model.StorageCell, error) {
+	args := m.Called(pvzID, code, capacity)
+	if cell := args.Get(0); cell != nil {
+		return cell.(*model.StorageCell), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCellService) UpdateCapacity(pvzID, cellID string, capacity int) (*model.StorageCell, error) {
+	args := m.Called(pvzID, cellID, capacity)
+	if cell := args.Get(0); cell != nil {
+		return cell.(*model.StorageCell), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCellService) Move(userID, productID, cellCode string) (*model.ProductLocation, error) {
+	args := m.Called(userID, productID, cellCode)
+	if location := args.Get(0); location != nil {
+		return location.(*model.ProductLocation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCellService) Locate(productID string) (*model.ProductLocation, error) {
+	args := m.Called(productID)
+	if location := args.Get(0); location != nil {
+		return location.(*model.ProductLocation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
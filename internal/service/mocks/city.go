@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCityService struct {
+	mock.Mock
+}
+
+func (m *MockCityService) List(activeOnly bool) ([]model.City, error) {
+	args := m.Called(activeOnly)
+	if cities := args.Get(0); cities != nil {
+		return cities.([]model.City), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCityService) Create(name string) (*model.City, error) {
+	args := m.Called(name)
+	if city := args.Get(0); city != nil {
+		return city.(*model.City), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCityService) Rename(actorID, id, name string) (*model.City, error) {
+	args := m.Called(actorID, id, name)
+	if city := args.Get(0); city != nil {
+		return city.(*model.City), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCityService) SetActive(id string, active bool) (*model.City, error) {
+	args := m.Called(id, active)
+	if city := args.Get(0); city != nil {
+		return city.(*model.City), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCityService) History(id string) ([]model.CityRename, error) {
+	args := m.Called(id)
+	if renames := args.Get(0); renames != nil {
+		return renames.([]model.CityRename), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInvitationService struct {
+	mock.Mock
+}
+
+func (m *MockInvitationService) Create(actorID string, email string, role model.UserRole) (*model.Invitation, string, error) {
+	args := m.Called(actorID, email, role)
+	if invitation := args.Get(0); invitation != nil {
+		return invitation.(*model.Invitation), args.String(1), args.Error(2)
+	}
+	return nil, args.String(1), args.Error(2)
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginAuditService struct {
+	mock.Mock
+}
+
+func (m *MockLoginAuditService) History(filter model.LoginAttemptFilter, page, limit int) ([]model.LoginAttempt, error) {
+	args := m.Called(filter, page, limit)
+	if attempts := args.Get(0); attempts != nil {
+		return attempts.([]model.LoginAttempt), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
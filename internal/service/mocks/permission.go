@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) HasPermission(role model.UserRole, permission model.Permission) (bool, error) {
+	args := m.Called(role, permission)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPermissionService) ListRoles() ([]model.Role, error) {
+	args := m.Called()
+	if roles := args.Get(0); roles != nil {
+		return roles.([]model.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPermissionService) CreateRole(name model.UserRole, description string, permissions []model.Permission) (*model.Role, error) {
+	args := m.Called(name, description, permissions)
+	if role := args.Get(0); role != nil {
+		return role.(*model.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPermissionService) SetPermissions(name model.UserRole, permissions []model.Permission) (*model.Role, error) {
+	args := m.Called(name, permissions)
+	if role := args.Get(0); role != nil {
+		return role.(*model.Role), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProductService struct {
+	mock.Mock
+}
+
+func (m *MockProductService) History(barcode string) ([]model.ProductHistoryEntry, error) {
+	args := m.Called(barcode)
+	if history := args.Get(0); history != nil {
+		return history.([]model.ProductHistoryEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) List(pvzID string, status model.ProductStatus, page, limit int) ([]model.Product, error) {
+	args := m.Called(pvzID, status, page, limit)
+	if products := args.Get(0); products != nil {
+		return products.([]model.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) MarkReady(userID, productID string) (*model.Product, string, error) {
+	args := m.Called(userID, productID)
+	if product := args.Get(0); product != nil {
+		return product.(*model.Product), args.String(1), args.Error(2)
+	}
+	return nil, args.String(1), args.Error(2)
+}
+
+func (m *MockProductService) Issue(userID, productID, code string) (*model.Product, error) {
+	args := m.Called(userID, productID, code)
+	if product := args.Get(0); product != nil {
+		return product.(*model.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProductTypeService struct {
+	mock.Mock
+}
+
+func (m *MockProductTypeService) List(activeOnly bool) ([]model.ProductTypeInfo, error) {
+	args := m.Called(activeOnly)
+	if productTypes := args.Get(0); productTypes != nil {
+		return productTypes.([]model.ProductTypeInfo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductTypeService) Create(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error) {
+	args := m.Called(code, names)
+	if productType := args.Get(0); productType != nil {
+		return productType.(*model.ProductTypeInfo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductTypeService) Rename(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error) {
+	args := m.Called(code, names)
+	if productType := args.Get(0); productType != nil {
+		return productType.(*model.ProductTypeInfo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductTypeService) SetActive(code model.ProductType, active bool) (*model.ProductTypeInfo, error) {
+	args := m.Called(code, active)
+	if productType := args.Get(0); productType != nil {
+		return productType.(*model.ProductTypeInfo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,90 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPvzService struct {
+	mock.Mock
+}
+
+func (m *MockPvzService) Create(pvz *model.PVZ) (*model.PVZ, error) {
+	args := m.Called(pvz)
+	if created := args.Get(0); created != nil {
+		return created.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) List(filter model.PvzFilter, page, limit int) ([]model.PvzListItem, error) {
+	args := m.Called(filter, page, limit)
+	if list := args.Get(0); list != nil {
+		return list.([]model.PvzListItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) SetCapacity(pvzID string, capacity model.PvzCapacity) (*model.PVZ, error) {
+	args := m.Called(pvzID, capacity)
+	if pvz := args.Get(0); pvz != nil {
+		return pvz.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Update(pvzID string, update model.PvzUpdate) (*model.PVZ, error) {
+	args := m.Called(pvzID, update)
+	if pvz := args.Get(0); pvz != nil {
+		return pvz.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Suspend(actorID, pvzID, reason string) (*model.PVZ, error) {
+	args := m.Called(actorID, pvzID, reason)
+	if pvz := args.Get(0); pvz != nil {
+		return pvz.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Activate(actorID, pvzID string) (*model.PVZ, error) {
+	args := m.Called(actorID, pvzID)
+	if pvz := args.Get(0); pvz != nil {
+		return pvz.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Close(actorID, pvzID, reason string) (*model.PVZ, error) {
+	args := m.Called(actorID, pvzID, reason)
+	if pvz := args.Get(0); pvz != nil {
+		return pvz.(*model.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Nearby(center model.GeoPoint, radius, page, limit int) ([]model.NearbyPvz, error) {
+	args := m.Called(center, radius, page, limit)
+	if list := args.Get(0); list != nil {
+		return list.([]model.NearbyPvz), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) Schedule(pvzID string) (*model.PvzSchedule, error) {
+	args := m.Called(pvzID)
+	if schedule := args.Get(0); schedule != nil {
+		return schedule.(*model.PvzSchedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPvzService) SetSchedule(schedule model.PvzSchedule) (*model.PvzSchedule, error) {
+	args := m.Called(schedule)
+	if updated := args.Get(0); updated != nil {
+		return updated.(*model.PvzSchedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
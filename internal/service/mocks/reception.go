@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReceptionService struct {
+	mock.Mock
+}
+
+func (m *MockReceptionService) OpenReception(userID string, role model.UserRole, pvzID string) (*model.Reception, error) {
+	args := m.Called(userID, role, pvzID)
+	if reception := args.Get(0); reception != nil {
+		return reception.(*model.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReceptionService) AddProduct(userID, pvzID string, product *model.Product, cellCode string) (*model.Product, error) {
+	args := m.Called(userID, pvzID, product, cellCode)
+	if product := args.Get(0); product != nil {
+		return product.(*model.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReceptionService) DeleteLastProduct(userID, pvzID string) (*model.Product, error) {
+	args := m.Called(userID, pvzID)
+	if product := args.Get(0); product != nil {
+		return product.(*model.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReceptionService) CloseLastReception(userID, pvzID string) (*model.Reception, error) {
+	args := m.Called(userID, pvzID)
+	if reception := args.Get(0); reception != nil {
+		return reception.(*model.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReceptionService) ReopenLastReception(actorID, pvzID, reason string) (*model.Reception, error) {
+	args := m.Called(actorID, pvzID, reason)
+	if reception := args.Get(0); reception != nil {
+		return reception.(*model.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReceptionService) Events(receptionID string) ([]model.ReceptionEvent, error) {
+	args := m.Called(receptionID)
+	if events := args.Get(0); events != nil {
+		return events.([]model.ReceptionEvent), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
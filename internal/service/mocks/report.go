@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) DailyReceptions(filter model.ReportFilter, page, limit int) ([]model.DailyReceptionStats, error) {
+	args := m.Called(filter, page, limit)
+	if stats := args.Get(0); stats != nil {
+		return stats.([]model.DailyReceptionStats), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReturnService struct {
+	mock.Mock
+}
+
+func (m *MockReturnService) OpenBatch(userID, pvzID string) (*model.ReturnBatch, error) {
+	args := m.Called(userID, pvzID)
+	if batch := args.Get(0); batch != nil {
+		return batch.(*model.ReturnBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReturnService) CloseLastBatch(userID, pvzID string) (*model.ReturnBatch, error) {
+	args := m.Called(userID, pvzID)
+	if batch := args.Get(0); batch != nil {
+		return batch.(*model.ReturnBatch), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReturnService) Return(userID, productID string, reason model.ReturnReason, comment string) (*model.ProductReturn, error) {
+	args := m.Called(userID, productID, reason, comment)
+	if productReturn := args.Get(0); productReturn != nil {
+		return productReturn.(*model.ProductReturn), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -9,20 +9,61 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) CreateToken(role model.UserRole) (string, error) {
-	args := m.Called(role)
+func (m *MockUserService) CreateToken(role model.UserRole, ip string, userAgent string) (string, error) {
+	args := m.Called(role, ip, userAgent)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockUserService) Register(email string, password string, role model.UserRole) (*model.User, error) {
-	args := m.Called(email, password, role)
+func (m *MockUserService) Register(email string, password string, role model.UserRole, invitationCode string) (*model.User, error) {
+	args := m.Called(email, password, role, invitationCode)
 	if user := args.Get(0); user != nil {
 		return user.(*model.User), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockUserService) Login(email string, password string) (string, error) {
-	args := m.Called(email, password)
+func (m *MockUserService) Login(email string, password string, ip string, userAgent string) (string, error) {
+	args := m.Called(email, password, ip, userAgent)
 	return args.String(0), args.Error(1)
 }
+
+func (m *MockUserService) Refresh(token string, ip string, userAgent string) (string, error) {
+	args := m.Called(token, ip, userAgent)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) ListSessions(userID string) ([]model.Session, error) {
+	args := m.Called(userID)
+	if sessions := args.Get(0); sessions != nil {
+		return sessions.([]model.Session), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserService) ParseToken(token string) (*model.TokenClaims, error) {
+	args := m.Called(token)
+	if claims := args.Get(0); claims != nil {
+		return claims.(*model.TokenClaims), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserService) Unlock(email string, ip string) error {
+	args := m.Called(email, ip)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ChangePassword(userID string, oldPassword string, newPassword string) error {
+	args := m.Called(userID, oldPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RequestPasswordReset(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ResetPassword(token string, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
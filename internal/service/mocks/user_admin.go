@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserAdminService struct {
+	mock.Mock
+}
+
+func (m *MockUserAdminService) List(email string, page, limit int) ([]model.User, error) {
+	args := m.Called(email, page, limit)
+	if users := args.Get(0); users != nil {
+		return users.([]model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserAdminService) Get(id string) (*model.User, error) {
+	args := m.Called(id)
+	if user := args.Get(0); user != nil {
+		return user.(*model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserAdminService) ChangeRole(actorID, id string, role model.UserRole) (*model.User, error) {
+	args := m.Called(actorID, id, role)
+	if user := args.Get(0); user != nil {
+		return user.(*model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserAdminService) SetDisabled(actorID, id string, disabled bool) (*model.User, error) {
+	args := m.Called(actorID, id, disabled)
+	if user := args.Get(0); user != nil {
+		return user.(*model.User), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserAdminService) Delete(actorID, id string) error {
+	args := m.Called(actorID, id)
+	return args.Error(0)
+}
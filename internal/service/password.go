@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+
+	// bcrypt молча обрезает всё, что длиннее 72 байт
+	bcryptMaxBytes = 72
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// PasswordPolicyError описывает, какому требованию не соответствует пароль
+type PasswordPolicyError struct {
+	Message string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Message
+}
+
+type PasswordPolicy struct {
+	cfg      config.PasswordPolicy
+	maxBytes int
+	denylist map[string]struct{}
+}
+
+// NewPasswordPolicy читает список запрещённых паролей из denylist_path
+func NewPasswordPolicy(cfg config.PasswordPolicy, hashing config.Hashing) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		cfg:      cfg,
+		maxBytes: cfg.MaxLength,
+		denylist: make(map[string]struct{}),
+	}
+
+	if hashing.Algorithm == "" || hashing.Algorithm == AlgorithmBcrypt {
+		if policy.maxBytes <= 0 || policy.maxBytes > bcryptMaxBytes {
+			policy.maxBytes = bcryptMaxBytes
+		}
+	}
+
+	if cfg.DenylistPath == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(cfg.DenylistPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open password denylist: %s", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		policy.denylist[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read password denylist: %s", err)
+	}
+
+	return policy, nil
+}
+
+func (p *PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		return &PasswordPolicyError{fmt.Sprintf("Password must be at least %d characters long", p.cfg.MinLength)}
+	}
+
+	if p.maxBytes > 0 && len(password) > p.maxBytes {
+		return &PasswordPolicyError{fmt.Sprintf("Password must be at most %d bytes long", p.maxBytes)}
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSpecial = true
+		}
+	}
+
+	switch {
+	case p.cfg.RequireUpper && !hasUpper:
+		return &PasswordPolicyError{"Password must contain an uppercase letter"}
+	case p.cfg.RequireLower && !hasLower:
+		return &PasswordPolicyError{"Password must contain a lowercase letter"}
+	case p.cfg.RequireDigit && !hasDigit:
+		return &PasswordPolicyError{"Password must contain a digit"}
+	case p.cfg.RequireSpecial && !hasSpecial:
+		return &PasswordPolicyError{"Password must contain a special character"}
+	}
+
+	if _, found := p.denylist[strings.ToLower(password)]; found {
+		return &PasswordPolicyError{"Password is too common"}
+	}
+
+	return nil
+}
+
+// PasswordHasher хеширует новые пароли выбранным алгоритмом,
+// но умеет проверять хеши обоих поддерживаемых алгоритмов
+type PasswordHasher struct {
+	cfg config.Hashing
+	// dummyHash - хеш с текущими параметрами, по нему проверяется пароль для неизвестной почты
+	dummyHash string
+}
+
+// dummyPassword хешируется при старте ради dummyHash, войти с ним нельзя
+const dummyPassword = "dummy-password-for-unknown-users"
+
+// NewPasswordHasher возвращает ошибку для неизвестного алгоритма, чтобы опечатка в конфиге
+// не приводила к молчаливому хешированию bcrypt
+func NewPasswordHasher(cfg config.Hashing) (*PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "":
+		cfg.Algorithm = AlgorithmBcrypt
+	case AlgorithmBcrypt, AlgorithmArgon2id:
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q, expected %q or %q",
+			cfg.Algorithm, AlgorithmBcrypt, AlgorithmArgon2id)
+	}
+
+	if cfg.BcryptCost == 0 {
+		cfg.BcryptCost = bcrypt.DefaultCost
+	}
+	if cfg.Argon2Time == 0 {
+		cfg.Argon2Time = 1
+	}
+	if cfg.Argon2Memory == 0 {
+		cfg.Argon2Memory = 64 * 1024
+	}
+	if cfg.Argon2Threads == 0 {
+		cfg.Argon2Threads = 2
+	}
+
+	hasher := &PasswordHasher{cfg: cfg}
+
+	dummyHash, err := hasher.Hash(dummyPassword)
+	if err != nil {
+		return nil, err
+	}
+	hasher.dummyHash = dummyHash
+
+	return hasher, nil
+}
+
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == AlgorithmArgon2id {
+		return h.hashArgon2id(password)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
+
+func (h *PasswordHasher) Verify(hash, password string) bool {
+	if strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$") {
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+
+		actual := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(actual, key) == 1
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// VerifyDummy тратит на проверку столько же времени, сколько Verify для настоящего хеша.
+// Вызывается, когда пользователя нет, чтобы время ответа не выдавало зарегистрированные почты
+func (h *PasswordHasher) VerifyDummy(password string) {
+	h.Verify(h.dummyHash, password)
+}
+
+// NeedsRehash сообщает, что хеш получен другим алгоритмом или с другими параметрами
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if h.cfg.Algorithm == AlgorithmArgon2id {
+		params, _, _, err := decodeArgon2id(hash)
+		if err != nil {
+			return true
+		}
+
+		return params.time != h.cfg.Argon2Time || params.memory != h.cfg.Argon2Memory || params.threads != h.cfg.Argon2Threads
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cfg.BcryptCost
+}
+
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// hashArgon2id кодирует хеш в формате PHC: $argon2id$v=19$m=65536,t=1,p=2$<salt>$<key>
+func (h *PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.cfg.Argon2Time, h.cfg.Argon2Memory, h.cfg.Argon2Threads, argon2KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgorithmArgon2id, argon2.Version, h.cfg.Argon2Memory, h.cfg.Argon2Time, h.cfg.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2 version")
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, errors.New("invalid argon2id parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+
+	return params, salt, key, nil
+}
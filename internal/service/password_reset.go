@@ -0,0 +1,141 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+)
+
+const (
+	resetTokenLength = 32
+	defaultResetTTL  = time.Hour
+)
+
+var (
+	ErrInvalidCurrentPassword = errors.New("invalid current password")
+	ErrInvalidResetToken      = errors.New("invalid or expired reset token")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrUserDisabled           = errors.New("user is disabled")
+)
+
+func (uS *userService) ChangePassword(userID string, oldPassword string, newPassword string) error {
+	user, err := uS.db.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if !uS.hasher.Verify(user.Password, oldPassword) {
+		return ErrInvalidCurrentPassword
+	}
+
+	return uS.setPassword(user.ID, newPassword)
+}
+
+// RequestPasswordReset не сообщает, существует ли пользователь:
+// для неизвестной почты запрос молча завершается без ошибки
+func (uS *userService) RequestPasswordReset(email string) error {
+	email = model.NormalizeEmail(email)
+
+	user, err := uS.db.FindByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	token, err := newSecret(resetTokenLength)
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token")
+	}
+
+	ttl := uS.resetTTL
+	if ttl <= 0 {
+		ttl = defaultResetTTL
+	}
+
+	// В базе хранится только хеш, сам токен уходит пользователю
+	if err := uS.db.CreatePasswordResetToken(user.ID, hashSecret(token), time.Now().Add(ttl)); err != nil {
+		return err
+	}
+
+	return uS.notifier.SendPasswordReset(user.Email, token)
+}
+
+// ResetPassword меняет пароль по токену сброса. Токен гасится в той же транзакции, что и смена пароля,
+// поэтому при ошибке он остаётся действующим. Все сессии пользователя завершаются
+func (uS *userService) ResetPassword(token string, newPassword string) error {
+	hashedPassword, err := uS.hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uS.db.ResetPassword(hashSecret(token), hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	if userID == "" {
+		return ErrInvalidResetToken
+	}
+
+	return nil
+}
+
+func (uS *userService) validatePassword(password string) error {
+	if uS.policy == nil {
+		return nil
+	}
+
+	return uS.policy.Validate(password)
+}
+
+// setPassword меняет пароль и завершает все сессии пользователя
+func (uS *userService) setPassword(userID string, password string) error {
+	hashedPassword, err := uS.hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return uS.db.ChangePassword(userID, hashedPassword)
+}
+
+func (uS *userService) hashPassword(password string) (string, error) {
+	if err := uS.validatePassword(password); err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := uS.hasher.Hash(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password")
+	}
+
+	return hashedPassword, nil
+}
+
+// newSecret генерирует случайный токен для передачи пользователю
+func newSecret(length int) (string, error) {
+	raw := make([]byte, length)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Секреты содержат достаточно случайных данных, поэтому медленный хеш не нужен
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	testBcrypt   = config.Hashing{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}
+	testArgon2id = config.Hashing{Algorithm: AlgorithmArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1}
+)
+
+func newTestHasher(t *testing.T, cfg config.Hashing) *PasswordHasher {
+	t.Helper()
+
+	hasher, err := NewPasswordHasher(cfg)
+	assert.NoError(t, err)
+
+	return hasher
+}
+
+func TestNewPasswordHasher_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name      string
+		algorithm string
+		expectErr bool
+	}{
+		{name: "default_bcrypt", algorithm: ""},
+		{name: "bcrypt", algorithm: AlgorithmBcrypt},
+		{name: "argon2id", algorithm: AlgorithmArgon2id},
+		{name: "typo", algorithm: "argon2", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher, err := NewPasswordHasher(config.Hashing{Algorithm: tc.algorithm})
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, hasher)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPasswordHasherRoundTrip_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    config.Hashing
+		prefix string
+	}{
+		{name: "bcrypt", cfg: testBcrypt, prefix: "$2a$"},
+		{name: "argon2id", cfg: testArgon2id, prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher := newTestHasher(t, tc.cfg)
+
+			hash, err := hasher.Hash("Secret-42")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tc.prefix), hash)
+
+			assert.True(t, hasher.Verify(hash, "Secret-42"))
+			assert.False(t, hasher.Verify(hash, "secret-42"))
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+}
+
+// Хеш прежнего алгоритма проверяется после смены конфига и помечается на перехеширование
+func TestPasswordHasherAlgorithmSwitch(t *testing.T) {
+	bcryptHash, err := newTestHasher(t, testBcrypt).Hash("Secret-42")
+	assert.NoError(t, err)
+
+	argonHash, err := newTestHasher(t, testArgon2id).Hash("Secret-42")
+	assert.NoError(t, err)
+
+	argon := newTestHasher(t, testArgon2id)
+	assert.True(t, argon.Verify(bcryptHash, "Secret-42"))
+	assert.True(t, argon.NeedsRehash(bcryptHash))
+
+	bcryptHasher := newTestHasher(t, testBcrypt)
+	assert.True(t, bcryptHasher.Verify(argonHash, "Secret-42"))
+	assert.True(t, bcryptHasher.NeedsRehash(argonHash))
+}
+
+func TestNeedsRehash_TableDriven(t *testing.T) {
+	bcryptHash, err := newTestHasher(t, testBcrypt).Hash("Secret-42")
+	assert.NoError(t, err)
+
+	argonHash, err := newTestHasher(t, testArgon2id).Hash("Secret-42")
+	assert.NoError(t, err)
+
+	strongerArgon := testArgon2id
+	strongerArgon.Argon2Time = 2
+
+	testCases := []struct {
+		name     string
+		cfg      config.Hashing
+		hash     string
+		expected bool
+	}{
+		{name: "same_bcrypt_cost", cfg: testBcrypt, hash: bcryptHash, expected: false},
+		{name: "bcrypt_cost_changed", cfg: config.Hashing{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}, hash: bcryptHash, expected: true},
+		{name: "same_argon2_params", cfg: testArgon2id, hash: argonHash, expected: false},
+		{name: "argon2_params_changed", cfg: strongerArgon, hash: argonHash, expected: true},
+		{name: "garbage_hash", cfg: testArgon2id, hash: "not-a-hash", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, newTestHasher(t, tc.cfg).NeedsRehash(tc.hash))
+		})
+	}
+}
+
+// bcrypt не принимает пароли длиннее 72 байт, поэтому политика для bcrypt режет длину в байтах
+func TestBcryptByteLimit(t *testing.T) {
+	// 37 кириллических букв - 74 байта, но всего 37 символов
+	long := "Aa1" + strings.Repeat("ж", 37)
+
+	_, err := newTestHasher(t, testBcrypt).Hash(long)
+	assert.Error(t, err)
+
+	_, err = newTestHasher(t, testArgon2id).Hash(long)
+	assert.NoError(t, err)
+
+	policy, err := NewPasswordPolicy(config.PasswordPolicy{MaxLength: 100}, testBcrypt)
+	assert.NoError(t, err)
+	assert.Error(t, policy.Validate(long))
+	assert.NoError(t, policy.Validate("Aa1"+strings.Repeat("ж", 34)))
+
+	policy, err = NewPasswordPolicy(config.PasswordPolicy{MaxLength: 100}, testArgon2id)
+	assert.NoError(t, err)
+	assert.NoError(t, policy.Validate(long))
+}
+
+// Хеш для неизвестной почты считается с текущими параметрами, иначе его проверка была бы
+// быстрее или медленнее проверки настоящего пароля
+func TestDummyHashMatchesConfig_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  config.Hashing
+	}{
+		{name: "bcrypt", cfg: testBcrypt},
+		{name: "argon2id", cfg: testArgon2id},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher := newTestHasher(t, tc.cfg)
+
+			assert.False(t, hasher.NeedsRehash(hasher.dummyHash))
+			assert.False(t, hasher.Verify(hasher.dummyHash, "Secret-42"))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+// permissionCacheTTL - как долго права ролей живут в памяти, прежде чем перечитать их из БД
+const permissionCacheTTL = 30 * time.Second
+
+var (
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrBuiltinRole       = errors.New("builtin role cannot be modified")
+	ErrInvalidRoleName   = errors.New("invalid role name")
+	ErrInvalidPermission = errors.New("invalid permission")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z_]{2,31}$`)
+
+type PermissionService interface {
+	HasPermission(role model.UserRole, permission model.Permission) (bool, error)
+	ListRoles() ([]model.Role, error)
+	CreateRole(name model.UserRole, description string, permissions []model.Permission) (*model.Role, error)
+	SetPermissions(name model.UserRole, permissions []model.Permission) (*model.Role, error)
+}
+
+type permissionService struct {
+	db repository.Database
+
+	mu       sync.RWMutex
+	cache    map[model.UserRole][]model.Permission
+	loadedAt time.Time
+}
+
+func NewPermissionService(db repository.Database) *permissionService {
+	return &permissionService{db: db}
+}
+
+// HasPermission проверяет право по кешу, который обновляется раз в permissionCacheTTL
+// и сразу после изменения ролей через этот сервис
+func (s *permissionService) HasPermission(role model.UserRole, permission model.Permission) (bool, error) {
+	s.mu.RLock()
+	cache, loadedAt := s.cache, s.loadedAt
+	s.mu.RUnlock()
+
+	if cache == nil || time.Since(loadedAt) > permissionCacheTTL {
+		var err error
+		if cache, err = s.reload(); err != nil {
+			return false, err
+		}
+	}
+
+	return slices.Contains(cache[role], permission), nil
+}
+
+func (s *permissionService) reload() (map[model.UserRole][]model.Permission, error) {
+	roles, err := s.db.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := make(map[model.UserRole][]model.Permission, len(roles))
+	for _, role := range roles {
+		cache[role.Name] = role.Permissions
+	}
+
+	s.mu.Lock()
+	s.cache, s.loadedAt = cache, time.Now()
+	s.mu.Unlock()
+
+	return cache, nil
+}
+
+func (s *permissionService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+func (s *permissionService) ListRoles() ([]model.Role, error) {
+	return s.db.ListRoles()
+}
+
+func (s *permissionService) CreateRole(name model.UserRole, description string, permissions []model.Permission) (*model.Role, error) {
+	if !roleNamePattern.MatchString(string(name)) {
+		return nil, ErrInvalidRoleName
+	}
+
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.db.CreateRole(&model.Role{Name: name, Description: description, Permissions: permissions})
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, ErrRoleExists
+	}
+
+	s.invalidate()
+
+	return role, nil
+}
+
+// SetPermissions заменяет права роли. Встроенные employee и moderator не меняются,
+// на их правах завязаны dummyLogin и бизнес-правила задания
+func (s *permissionService) SetPermissions(name model.UserRole, permissions []model.Permission) (*model.Role, error) {
+	if err := validatePermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.db.FindRole(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == nil {
+		return nil, ErrUnknownRole
+	}
+
+	if role.Builtin {
+		return nil, ErrBuiltinRole
+	}
+
+	if err := s.db.SetRolePermissions(name, permissions); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+
+	return s.db.FindRole(name)
+}
+
+func validatePermissions(permissions []model.Permission) error {
+	for _, permission := range permissions {
+		if !slices.Contains(model.Permissions, permission) {
+			return ErrInvalidPermission
+		}
+	}
+
+	return nil
+}
+
+// roleExists используется сервисами, которые назначают роль пользователю
+func roleExists(db repository.Database, name model.UserRole) error {
+	role, err := db.FindRole(name)
+	if err != nil {
+		return err
+	}
+
+	if role == nil {
+		return ErrUnknownRole
+	}
+
+	return nil
+}
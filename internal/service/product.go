@@ -0,0 +1,240 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const (
+	issueCodeDigits = 6
+	// maxIssueAttempts - после стольких неверных кодов нужно заново перевести товар в ready
+	maxIssueAttempts = 5
+
+	// Ограничения сведений о товаре: 1 т, 10 м по каждой стороне, 100 млн ₽ в копейках
+	maxWeightGrams       = 1_000_000
+	maxDimensionMm       = 10_000
+	maxDeclaredValue     = 10_000_000_000
+	maxProductNoteLength = 500
+)
+
+var (
+	ErrInvalidBarcode          = errors.New("invalid barcode")
+	ErrBarcodeDuplicate        = errors.New("barcode already accepted")
+	ErrProductNotFound         = errors.New("product not found")
+	ErrUnknownProductStatus    = errors.New("unknown product status")
+	ErrProductStatusTransition = errors.New("product status does not allow this operation")
+	ErrReceptionNotClosed      = errors.New("product reception is not closed yet")
+	ErrInvalidIssueCode        = errors.New("invalid issue code")
+	ErrIssueCodeLocked         = errors.New("too many invalid issue codes")
+	ErrInvalidWeight           = errors.New("invalid product weight")
+	ErrInvalidDimensions       = errors.New("invalid product dimensions")
+	ErrInvalidDeclaredValue    = errors.New("invalid declared value")
+	ErrUnknownCondition        = errors.New("unknown product condition")
+	ErrNoteTooLong             = errors.New("product note is too long")
+)
+
+// Штрихкод или номер заказа: 6-32 символа, латиница и цифры, дефисы только внутри
+var barcodePattern = regexp.MustCompile(`^[0-9A-Z][0-9A-Z-]{4,30}[0-9A-Z]$`)
+
+// ProductService - поиск товаров и их выдача. Выдача доступна
+// только сотрудникам, назначенным на ПВЗ товара, и только после закрытия приёмки
+type ProductService interface {
+	History(barcode string) ([]model.ProductHistoryEntry, error)
+	List(pvzID string, status model.ProductStatus, page, limit int) ([]model.Product, error)
+	MarkReady(userID, productID string) (*model.Product, string, error)
+	Issue(userID, productID, code string) (*model.Product, error)
+}
+
+type productService struct {
+	db repository.Database
+}
+
+func NewProductService(db repository.Database) *productService {
+	return &productService{db}
+}
+
+// History возвращает все приёмы товара по штрихкоду на любых ПВЗ
+func (s *productService) History(barcode string) ([]model.ProductHistoryEntry, error) {
+	barcode = model.NormalizeBarcode(barcode)
+
+	if !barcodePattern.MatchString(barcode) {
+		return nil, ErrInvalidBarcode
+	}
+
+	return s.db.ListProductsByBarcode(barcode)
+}
+
+func (s *productService) List(pvzID string, status model.ProductStatus, page, limit int) ([]model.Product, error) {
+	if err := validateProductStatus(status); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrPvzNotFound
+	}
+
+	return s.db.ListProducts(pvzID, status, limit, (page-1)*limit)
+}
+
+// MarkReady готовит товар к выдаче и возвращает код для получателя.
+// Код показывается один раз, в базе хранится только его хеш
+func (s *productService) MarkReady(userID, productID string) (*model.Product, string, error) {
+	if _, err := issuable(s.db, userID, productID); err != nil {
+		return nil, "", err
+	}
+
+	code, err := newIssueCode()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate issue code")
+	}
+
+	product, err := s.db.MarkProductReady(productID, hashIssueCode(productID, code))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if product == nil {
+		return nil, "", ErrProductStatusTransition
+	}
+
+	return product, code, nil
+}
+
+func (s *productService) Issue(userID, productID, code string) (*model.Product, error) {
+	entry, err := issuable(s.db, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != model.ProductReady {
+		return nil, ErrProductStatusTransition
+	}
+
+	product, err := s.db.IssueProduct(productID, hashIssueCode(productID, code), userID, maxIssueAttempts)
+	switch {
+	case errors.Is(err, repository.ErrInvalidIssueCode):
+		return nil, ErrInvalidIssueCode
+	case errors.Is(err, repository.ErrIssueCodeLocked):
+		return nil, ErrIssueCodeLocked
+	case err != nil:
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, ErrProductStatusTransition
+	}
+
+	return product, nil
+}
+
+// issuable находит товар и проверяет, что сотрудник работает на его ПВЗ, а приёмка закрыта.
+// Те же условия действуют для возврата
+func issuable(db repository.Database, userID, productID string) (*model.ProductHistoryEntry, error) {
+	entry, err := db.FindProductWithReception(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		return nil, ErrProductNotFound
+	}
+
+	if err := authorizeEmployee(db, userID, entry.PvzID); err != nil {
+		return nil, err
+	}
+
+	if entry.ReceptionStatus != model.ReceptionClose {
+		return nil, ErrReceptionNotClosed
+	}
+
+	return entry, nil
+}
+
+func validateProductStatus(status model.ProductStatus) error {
+	if status != "" && !slices.Contains(model.ProductStatuses, status) {
+		return ErrUnknownProductStatus
+	}
+
+	return nil
+}
+
+// newIssueCode генерирует цифровой код, который удобно продиктовать на выдаче
+func newIssueCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", issueCodeDigits, n.Int64()), nil
+}
+
+// Код короткий, поэтому хеш привязан к товару: одинаковые коды разных товаров не совпадают
+func hashIssueCode(productID, code string) string {
+	return hashSecret(productID + ":" + code)
+}
+
+// validateBarcode проверяет формат штрихкода. Повторный приём проверяет хранилище при добавлении товара.
+// Товар без штрихкода допустим: старые клиенты передают только тип
+func validateBarcode(barcode string) error {
+	if barcode != "" && !barcodePattern.MatchString(barcode) {
+		return ErrInvalidBarcode
+	}
+
+	return nil
+}
+
+// checkProductDetails проверяет необязательные сведения о товаре и возвращает их в нормализованном виде.
+// Пустое состояние означает ok
+func checkProductDetails(product *model.Product) (*model.Product, error) {
+	details := &model.Product{
+		WeightGrams:   product.WeightGrams,
+		Dimensions:    product.Dimensions,
+		DeclaredValue: product.DeclaredValue,
+		Condition:     product.Condition,
+		Note:          strings.TrimSpace(product.Note),
+	}
+
+	if details.WeightGrams != nil && (*details.WeightGrams <= 0 || *details.WeightGrams > maxWeightGrams) {
+		return nil, ErrInvalidWeight
+	}
+
+	if dims := details.Dimensions; dims != nil {
+		for _, side := range []int{dims.LengthMm, dims.WidthMm, dims.HeightMm} {
+			if side <= 0 || side > maxDimensionMm {
+				return nil, ErrInvalidDimensions
+			}
+		}
+	}
+
+	if details.DeclaredValue != nil && (*details.DeclaredValue < 0 || *details.DeclaredValue > maxDeclaredValue) {
+		return nil, ErrInvalidDeclaredValue
+	}
+
+	if details.Condition == "" {
+		details.Condition = model.ConditionOK
+	}
+
+	if !slices.Contains(model.ProductConditions, details.Condition) {
+		return nil, ErrUnknownCondition
+	}
+
+	if utf8.RuneCountInString(details.Note) > maxProductNoteLength {
+		return nil, ErrNoteTooLong
+	}
+
+	return details, nil
+}
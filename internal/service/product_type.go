@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+// defaultLocale - язык, название на котором обязательно у каждого типа
+const defaultLocale = "ru"
+
+var (
+	ErrProductTypeNotFound     = errors.New("product type not found")
+	ErrProductTypeExists       = errors.New("product type already exists")
+	ErrInvalidProductTypeCode  = errors.New("invalid product type code")
+	ErrInvalidProductTypeNames = errors.New("invalid product type names")
+	ErrUnknownProductType      = errors.New("unknown or inactive product type")
+)
+
+var (
+	productTypeCodePattern = regexp.MustCompile(`^[\p{Ll}0-9_-]{2,32}$`)
+	localePattern          = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+type ProductTypeService interface {
+	List(activeOnly bool) ([]model.ProductTypeInfo, error)
+	Create(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error)
+	Rename(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error)
+	SetActive(code model.ProductType, active bool) (*model.ProductTypeInfo, error)
+}
+
+type productTypeService struct {
+	db repository.Database
+}
+
+func NewProductTypeService(db repository.Database) *productTypeService {
+	return &productTypeService{db}
+}
+
+func (s *productTypeService) List(activeOnly bool) ([]model.ProductTypeInfo, error) {
+	return s.db.ListProductTypes(activeOnly)
+}
+
+// Create добавляет тип. Код потом не меняется: он хранится в товарах и приходит в POST /products
+func (s *productTypeService) Create(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error) {
+	if !productTypeCodePattern.MatchString(string(code)) {
+		return nil, ErrInvalidProductTypeCode
+	}
+
+	names, err := normalizeProductTypeNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	productType, err := s.db.CreateProductType(&model.ProductTypeInfo{Code: code, Names: names})
+	if err != nil {
+		return nil, err
+	}
+
+	if productType == nil {
+		return nil, ErrProductTypeExists
+	}
+
+	return productType, nil
+}
+
+// Rename заменяет отображаемые названия целиком
+func (s *productTypeService) Rename(code model.ProductType, names map[string]string) (*model.ProductTypeInfo, error) {
+	names, err := normalizeProductTypeNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.get(code); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.UpdateProductTypeNames(code, names); err != nil {
+		return nil, err
+	}
+
+	return s.get(code)
+}
+
+// SetActive включает или выключает тип. Неактивный тип нельзя указать у нового товара,
+// уже принятые товары не меняются
+func (s *productTypeService) SetActive(code model.ProductType, active bool) (*model.ProductTypeInfo, error) {
+	if _, err := s.get(code); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetProductTypeActive(code, active); err != nil {
+		return nil, err
+	}
+
+	return s.get(code)
+}
+
+func (s *productTypeService) get(code model.ProductType) (*model.ProductTypeInfo, error) {
+	productType, err := s.db.FindProductType(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if productType == nil {
+		return nil, ErrProductTypeNotFound
+	}
+
+	return productType, nil
+}
+
+func normalizeProductTypeNames(names map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(names))
+
+	for locale, name := range names {
+		name = strings.TrimSpace(name)
+		if !localePattern.MatchString(locale) || name == "" || utf8.RuneCountInString(name) > 100 {
+			return nil, ErrInvalidProductTypeNames
+		}
+		normalized[locale] = name
+	}
+
+	if _, found := normalized[defaultLocale]; !found {
+		return nil, ErrInvalidProductTypeNames
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,588 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+var (
+	ErrCityNotAllowed  = errors.New("pvz cannot be opened in this city")
+	ErrPvzExists       = errors.New("pvz already exists")
+	ErrInvalidTimezone = errors.New("unknown time zone")
+	ErrInvalidCapacity = errors.New("invalid pvz capacity")
+	ErrPvzFieldTooLong = errors.New("pvz name or address is too long")
+	ErrInvalidPostal   = errors.New("invalid postal code")
+	ErrInvalidLocation = errors.New("invalid coordinates")
+	ErrInvalidRadius   = errors.New("invalid search radius")
+	ErrReasonTooLong   = errors.New("status reason is too long")
+	ErrInvalidSchedule = errors.New("invalid opening hours")
+
+	ErrUnknownPvzStatus    = errors.New("unknown pvz status")
+	ErrPvzClosed           = errors.New("pvz is closed")
+	ErrPvzStatusConflict   = errors.New("pvz status does not allow this change")
+	ErrPvzHasOpenReception = errors.New("pvz has an open reception")
+	ErrPvzNotEmpty         = errors.New("pvz still has products")
+)
+
+const (
+	// maxPvzCapacity - верхняя граница лимита, чтобы опечатка не отключала проверку
+	maxPvzCapacity = 100000
+
+	maxPvzFieldLength     = 200
+	maxStatusReasonLength = 500
+
+	// DefaultNearbyRadius и MaxNearbyRadius - радиус поиска ближайших ПВЗ в метрах
+	DefaultNearbyRadius = 5000
+	MaxNearbyRadius     = 50000
+)
+
+// postalCodePattern - российский почтовый индекс
+var postalCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+type PvzService interface {
+	Create(pvz *model.PVZ) (*model.PVZ, error)
+	List(filter model.PvzFilter, page, limit int) ([]model.PvzListItem, error)
+	SetCapacity(pvzID string, capacity model.PvzCapacity) (*model.PVZ, error)
+	Update(pvzID string, update model.PvzUpdate) (*model.PVZ, error)
+	Suspend(actorID, pvzID, reason string) (*model.PVZ, error)
+	Activate(actorID, pvzID string) (*model.PVZ, error)
+	Close(actorID, pvzID, reason string) (*model.PVZ, error)
+	Nearby(center model.GeoPoint, radius, page, limit int) ([]model.NearbyPvz, error)
+	Schedule(pvzID string) (*model.PvzSchedule, error)
+	SetSchedule(schedule model.PvzSchedule) (*model.PvzSchedule, error)
+}
+
+type pvzService struct {
+	db repository.Database
+}
+
+func NewPvzService(db repository.Database) *pvzService {
+	return &pvzService{db}
+}
+
+// Create заводит ПВЗ, если город есть в справочнике и активен.
+// Название города берётся из справочника, чтобы не зависеть от регистра в запросе
+func (s *pvzService) Create(pvz *model.PVZ) (*model.PVZ, error) {
+	timezone := pvz.Timezone
+	if timezone == "" {
+		timezone = model.DefaultTimezone
+	}
+
+	if err := validateTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	name, address := strings.TrimSpace(pvz.Name), normalizeAddress(pvz.Address)
+	if err := validatePvzFields(name, address, pvz.Location); err != nil {
+		return nil, err
+	}
+
+	city, err := s.db.FindCityByName(pvz.City)
+	if err != nil {
+		return nil, err
+	}
+
+	if city == nil || !city.Active {
+		return nil, ErrCityNotAllowed
+	}
+
+	created, err := s.db.CreatePvz(&model.PVZ{
+		ID:               pvz.ID,
+		RegistrationDate: pvz.RegistrationDate,
+		City:             city.Name,
+		Timezone:         timezone,
+		Name:             name,
+		Address:          address,
+		Location:         pvz.Location,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created == nil {
+		return nil, ErrPvzExists
+	}
+
+	return created, nil
+}
+
+// List собирает страницу ПВЗ с приёмками, товарами и партиями возвратов
+// фиксированным числом запросов, без запроса на каждый ПВЗ
+func (s *pvzService) List(filter model.PvzFilter, page, limit int) ([]model.PvzListItem, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if err := validateProductStatus(filter.ProductStatus); err != nil {
+		return nil, err
+	}
+
+	if filter.Status != "" && !slices.Contains(model.PvzStatuses, filter.Status) {
+		return nil, ErrUnknownPvzStatus
+	}
+
+	pvzs, err := s.db.ListPvz(filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pvzs) == 0 {
+		return []model.PvzListItem{}, nil
+	}
+
+	pvzIDs := make([]string, len(pvzs))
+	for i, pvz := range pvzs {
+		pvzIDs[i] = pvz.ID
+	}
+
+	if err := s.fillUtilization(pvzs, pvzIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillOpenNow(pvzs, pvzIDs); err != nil {
+		return nil, err
+	}
+
+	receptions, err := s.db.ListReceptions(pvzIDs, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	receptionIDs := make([]string, len(receptions))
+	for i, reception := range receptions {
+		receptionIDs[i] = reception.ID
+	}
+
+	products, err := s.db.ListProductsByReceptions(receptionIDs, filter.ProductStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	productsByReception := make(map[string][]model.Product)
+	for _, product := range products {
+		productsByReception[product.ReceptionID] = append(productsByReception[product.ReceptionID], product)
+	}
+
+	receptionsByPvz := make(map[string][]model.ReceptionWithProducts)
+	for _, reception := range receptions {
+		items := productsByReception[reception.ID]
+		if items == nil {
+			items = []model.Product{}
+		}
+
+		receptionsByPvz[reception.PvzID] = append(receptionsByPvz[reception.PvzID],
+			model.ReceptionWithProducts{Reception: reception, Products: items})
+	}
+
+	batchesByPvz, err := s.returnBatches(pvzIDs, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]model.PvzListItem, len(pvzs))
+	for i, pvz := range pvzs {
+		items := receptionsByPvz[pvz.ID]
+		if items == nil {
+			items = []model.ReceptionWithProducts{}
+		}
+
+		batches := batchesByPvz[pvz.ID]
+		if batches == nil {
+			batches = []model.ReturnBatchWithReturns{}
+		}
+
+		list[i] = model.PvzListItem{Pvz: pvz, Receptions: items, ReturnBatches: batches}
+	}
+
+	return list, nil
+}
+
+// SetCapacity заменяет лимиты ПВЗ. Лимит можно опустить ниже текущей занятости:
+// новые товары не примутся, пока ПВЗ не разгрузится
+func (s *pvzService) SetCapacity(pvzID string, capacity model.PvzCapacity) (*model.PVZ, error) {
+	if capacity.Total < 0 || capacity.Total > maxPvzCapacity {
+		return nil, ErrInvalidCapacity
+	}
+
+	for productType, limit := range capacity.ByType {
+		if limit <= 0 || limit > maxPvzCapacity {
+			return nil, ErrInvalidCapacity
+		}
+
+		info, err := s.db.FindProductType(productType)
+		if err != nil {
+			return nil, err
+		}
+
+		if info == nil {
+			return nil, ErrUnknownProductType
+		}
+	}
+
+	pvz, err := s.db.FindPvz(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pvz == nil {
+		return nil, ErrPvzNotFound
+	}
+
+	if err := s.db.SetPvzCapacity(pvzID, capacity); err != nil {
+		return nil, err
+	}
+
+	pvzs := []model.PVZ{*pvz}
+	if err := s.fillUtilization(pvzs, []string{pvzID}); err != nil {
+		return nil, err
+	}
+
+	return &pvzs[0], nil
+}
+
+// Update меняет только переданные поля. Закрытый ПВЗ не редактируется
+func (s *pvzService) Update(pvzID string, update model.PvzUpdate) (*model.PVZ, error) {
+	pvz, err := s.db.FindPvz(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pvz == nil {
+		return nil, ErrPvzNotFound
+	}
+
+	if pvz.Status == model.PvzClosed {
+		return nil, ErrPvzClosed
+	}
+
+	if update.Name != nil {
+		pvz.Name = strings.TrimSpace(*update.Name)
+	}
+
+	if update.Address != nil {
+		pvz.Address = normalizeAddress(*update.Address)
+	}
+
+	if update.Location != nil {
+		pvz.Location = update.Location
+	}
+
+	if update.Timezone != nil {
+		pvz.Timezone = *update.Timezone
+	}
+
+	if err := validatePvzFields(pvz.Name, pvz.Address, pvz.Location); err != nil {
+		return nil, err
+	}
+
+	if err := validateTimezone(pvz.Timezone); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.db.UpdatePvz(pvz)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		return nil, ErrPvzNotFound
+	}
+
+	return updated, nil
+}
+
+// Suspend приостанавливает ПВЗ: открытая приёмка доводится до конца, новые не открываются
+func (s *pvzService) Suspend(actorID, pvzID, reason string) (*model.PVZ, error) {
+	return s.changeStatus(actorID, pvzID, reason, model.PvzSuspended, model.PvzActive)
+}
+
+func (s *pvzService) Activate(actorID, pvzID string) (*model.PVZ, error) {
+	return s.changeStatus(actorID, pvzID, "", model.PvzActive, model.PvzSuspended)
+}
+
+// Close закрывает ПВЗ навсегда. На ПВЗ не должно быть открытой приёмки и товаров,
+// включая возвраты, которые ещё не отправлены
+func (s *pvzService) Close(actorID, pvzID, reason string) (*model.PVZ, error) {
+	return s.changeStatus(actorID, pvzID, reason, model.PvzClosed, model.PvzActive, model.PvzSuspended)
+}
+
+func (s *pvzService) changeStatus(actorID, pvzID, reason string, to model.PvzStatus, from ...model.PvzStatus) (*model.PVZ, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return nil, ErrReasonTooLong
+	}
+
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrPvzNotFound
+	}
+
+	pvz, err := s.db.ChangePvzStatus(pvzID, from, to, reason, actorID)
+	switch {
+	case errors.Is(err, repository.ErrPvzHasOpenReception):
+		return nil, ErrPvzHasOpenReception
+	case errors.Is(err, repository.ErrPvzNotEmpty):
+		return nil, ErrPvzNotEmpty
+	case err != nil:
+		return nil, err
+	}
+
+	if pvz == nil {
+		return nil, ErrPvzStatusConflict
+	}
+
+	return pvz, nil
+}
+
+// Nearby ищет активные ПВЗ в радиусе radius метров от точки, ближние первыми
+func (s *pvzService) Nearby(center model.GeoPoint, radius, page, limit int) ([]model.NearbyPvz, error) {
+	if !center.Valid() {
+		return nil, ErrInvalidLocation
+	}
+
+	if radius <= 0 || radius > MaxNearbyRadius {
+		return nil, ErrInvalidRadius
+	}
+
+	return s.db.NearbyPvz(center, float64(radius), limit, (page-1)*limit)
+}
+
+func (s *pvzService) Schedule(pvzID string) (*model.PvzSchedule, error) {
+	exists, err := s.db.PvzExists(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrPvzNotFound
+	}
+
+	schedules, err := s.db.ListPvzSchedules([]string{pvzID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedules[0], nil
+}
+
+// SetSchedule заменяет график ПВЗ целиком: недельные часы и все исключения
+func (s *pvzService) SetSchedule(schedule model.PvzSchedule) (*model.PvzSchedule, error) {
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.db.PvzExists(schedule.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrPvzNotFound
+	}
+
+	if err := s.db.SetPvzSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	return s.Schedule(schedule.PvzID)
+}
+
+// fillOpenNow считает «открыт сейчас» по местному времени каждого ПВЗ.
+// Исключения берутся начиная со вчерашней даты: в часовых поясах ПВЗ может быть другой день
+func (s *pvzService) fillOpenNow(pvzs []model.PVZ, pvzIDs []string) error {
+	now := time.Now()
+	since := now.AddDate(0, 0, -1)
+
+	schedules, err := s.db.ListPvzSchedules(pvzIDs, &since)
+	if err != nil {
+		return err
+	}
+
+	for i := range pvzs {
+		location, err := time.LoadLocation(pvzs[i].Timezone)
+		if err != nil {
+			return err
+		}
+
+		open := schedules[i].OpenAt(now.In(location))
+		pvzs[i].OpenNow = &open
+	}
+
+	return nil
+}
+
+// fillUtilization проставляет ПВЗ лимиты и текущую занятость двумя запросами на всю страницу
+func (s *pvzService) fillUtilization(pvzs []model.PVZ, pvzIDs []string) error {
+	capacities, err := s.db.ListPvzCapacities(pvzIDs)
+	if err != nil {
+		return err
+	}
+
+	usage, err := s.db.ListPvzUsage(pvzIDs)
+	if err != nil {
+		return err
+	}
+
+	capacityByPvz := make(map[string]*model.PvzCapacity)
+	utilizationByPvz := make(map[string]*model.PvzUtilization)
+	for _, id := range pvzIDs {
+		capacityByPvz[id] = &model.PvzCapacity{ByType: map[model.ProductType]int{}}
+		utilizationByPvz[id] = &model.PvzUtilization{ByType: map[model.ProductType]int{}}
+	}
+
+	for _, count := range capacities {
+		if count.Type == "" {
+			capacityByPvz[count.PvzID].Total = count.Count
+		} else {
+			capacityByPvz[count.PvzID].ByType[count.Type] = count.Count
+		}
+	}
+
+	for _, count := range usage {
+		utilizationByPvz[count.PvzID].Total += count.Count
+		utilizationByPvz[count.PvzID].ByType[count.Type] = count.Count
+	}
+
+	for i := range pvzs {
+		pvzs[i].Capacity = capacityByPvz[pvzs[i].ID]
+		pvzs[i].Utilization = utilizationByPvz[pvzs[i].ID]
+	}
+
+	return nil
+}
+
+// returnBatches собирает партии возвратов страницы ПВЗ вместе с их возвратами
+func (s *pvzService) returnBatches(pvzIDs []string, filter model.PvzFilter) (map[string][]model.ReturnBatchWithReturns, error) {
+	batches, err := s.db.ListReturnBatches(pvzIDs, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+
+	batchIDs := make([]string, len(batches))
+	for i, batch := range batches {
+		batchIDs[i] = batch.ID
+	}
+
+	returns, err := s.db.ListReturnsByBatches(batchIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	returnsByBatch := make(map[string][]model.ProductReturn)
+	for _, productReturn := range returns {
+		returnsByBatch[productReturn.BatchID] = append(returnsByBatch[productReturn.BatchID], productReturn)
+	}
+
+	batchesByPvz := make(map[string][]model.ReturnBatchWithReturns)
+	for _, batch := range batches {
+		items := returnsByBatch[batch.ID]
+		if items == nil {
+			items = []model.ProductReturn{}
+		}
+
+		batchesByPvz[batch.PvzID] = append(batchesByPvz[batch.PvzID],
+			model.ReturnBatchWithReturns{Batch: batch, Returns: items})
+	}
+
+	return batchesByPvz, nil
+}
+
+func normalizeAddress(address model.PvzAddress) model.PvzAddress {
+	return model.PvzAddress{
+		PostalCode: strings.TrimSpace(address.PostalCode),
+		Street:     strings.TrimSpace(address.Street),
+		House:      strings.TrimSpace(address.House),
+	}
+}
+
+func validatePvzFields(name string, address model.PvzAddress, location *model.GeoPoint) error {
+	for _, field := range []string{name, address.Street, address.House} {
+		if utf8.RuneCountInString(field) > maxPvzFieldLength {
+			return ErrPvzFieldTooLong
+		}
+	}
+
+	if address.PostalCode != "" && !postalCodePattern.MatchString(address.PostalCode) {
+		return ErrInvalidPostal
+	}
+
+	if location != nil && !location.Valid() {
+		return ErrInvalidLocation
+	}
+
+	return nil
+}
+
+func validateSchedule(schedule model.PvzSchedule) error {
+	weekdays := make(map[int]bool)
+	for _, hours := range schedule.Weekly {
+		if hours.Weekday < 1 || hours.Weekday > 7 || weekdays[hours.Weekday] {
+			return ErrInvalidSchedule
+		}
+		weekdays[hours.Weekday] = true
+
+		if err := validateInterval(hours.Opens, hours.Closes); err != nil {
+			return err
+		}
+	}
+
+	dates := make(map[string]bool)
+	for _, exception := range schedule.Exceptions {
+		if _, err := time.Parse(time.DateOnly, exception.Date); err != nil || dates[exception.Date] {
+			return ErrInvalidSchedule
+		}
+		dates[exception.Date] = true
+
+		if utf8.RuneCountInString(exception.Note) > maxPvzFieldLength {
+			return ErrInvalidSchedule
+		}
+
+		if exception.Closed() {
+			continue
+		}
+
+		if err := validateInterval(exception.Opens, exception.Closes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateInterval(opens, closes string) error {
+	from, err := model.ParseClock(opens)
+	if err != nil || from == 24*60 {
+		return ErrInvalidSchedule
+	}
+
+	to, err := model.ParseClock(closes)
+	if err != nil || to <= from {
+		return ErrInvalidSchedule
+	}
+
+	return nil
+}
+
+// validateTimezone принимает только явные имена IANA: "Local" зависит от сервера, а не от ПВЗ
+func validateTimezone(name string) error {
+	if name == "Local" {
+		return ErrInvalidTimezone
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimezone
+	}
+
+	return nil
+}
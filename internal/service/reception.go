@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+var (
+	ErrPvzNotFound         = errors.New("pvz not found")
+	ErrPvzNotActive        = errors.New("pvz is suspended or closed")
+	ErrNotAssigned         = errors.New("employee is not assigned to this pvz")
+	ErrReceptionInProgress = errors.New("pvz already has an open reception")
+	ErrNoOpenReception     = errors.New("pvz has no open reception")
+	ErrNoProducts          = errors.New("open reception has no products")
+	ErrOutsideHours        = errors.New("pvz is closed at this time")
+	ErrPvzFull             = errors.New("pvz is full")
+	ErrPvzTypeFull         = errors.New("pvz has no room for this product type")
+	ErrNoClosedReception   = errors.New("pvz has no closed reception to reopen")
+	ErrReopenDisabled      = errors.New("reception reopen is disabled")
+	ErrReopenExpired       = errors.New("reception reopen window has expired")
+	ErrReasonRequired      = errors.New("reason is required")
+	ErrReceptionProcessed  = errors.New("reception products have already been processed")
+	ErrReopenConflict      = errors.New("reception changed while reopening")
+)
+
+// ReceptionService - операции сотрудника ПВЗ с приёмками и товарами.
+// Все методы принимают id сотрудника и проверяют, что он назначен на этот ПВЗ
+type ReceptionService interface {
+	OpenReception(userID string, role model.UserRole, pvzID string) (*model.Reception, error)
+	AddProduct(userID, pvzID string, product *model.Product, cellCode string) (*model.Product, error)
+	DeleteLastProduct(userID, pvzID string) (*model.Product, error)
+	CloseLastReception(userID, pvzID string) (*model.Reception, error)
+	// ReopenLastReception вызывает модератор, назначение на ПВЗ не требуется
+	ReopenLastReception(actorID, pvzID, reason string) (*model.Reception, error)
+	Events(receptionID string) ([]model.ReceptionEvent, error)
+}
+
+type receptionService struct {
+	db          repository.Database
+	cfg         config.Receptions
+	permissions PermissionService
+}
+
+func NewReceptionService(db repository.Database, cfg config.Receptions, permissions PermissionService) *receptionService {
+	return &receptionService{db, cfg, permissions}
+}
+
+// OpenReception открывает приёмку в часы работы ПВЗ. Вне графика нужна роль с правом reception.override_hours
+func (s *receptionService) OpenReception(userID string, role model.UserRole, pvzID string) (*model.Reception, error) {
+	if err := s.authorize(userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkHours(role, pvzID); err != nil {
+		return nil, err
+	}
+
+	reception, err := s.db.CreateReception(pvzID)
+	if errors.Is(err, repository.ErrPvzNotActive) {
+		return nil, ErrPvzNotActive
+	} else if err != nil {
+		return nil, err
+	}
+
+	if reception == nil {
+		return nil, ErrReceptionInProgress
+	}
+
+	return reception, nil
+}
+
+// AddProduct добавляет товар в открытую приёмку. Непустой cellCode сразу размещает товар в ячейке ПВЗ
+func (s *receptionService) AddProduct(userID, pvzID string, product *model.Product, cellCode string) (*model.Product, error) {
+	if err := s.authorize(userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	details, err := checkProductDetails(product)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.db.FindProductType(product.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil || !info.Active {
+		return nil, ErrUnknownProductType
+	}
+
+	barcode := model.NormalizeBarcode(product.Barcode)
+	if err := validateBarcode(barcode); err != nil {
+		return nil, err
+	}
+
+	var cellID string
+	if cellCode != "" {
+		cell, err := s.db.FindCellByCode(pvzID, model.NormalizeCellCode(cellCode))
+		if err != nil {
+			return nil, err
+		}
+
+		if cell == nil {
+			return nil, ErrCellNotFound
+		}
+
+		cellID = cell.ID
+	}
+
+	details.Type, details.Barcode, details.CellID = product.Type, barcode, cellID
+
+	created, err := s.db.CreateProduct(pvzID, userID, details, time.Now().Add(-s.cfg.BarcodeDuplicateWindow))
+	switch {
+	case errors.Is(err, repository.ErrBarcodeDuplicate):
+		return nil, ErrBarcodeDuplicate
+	case errors.Is(err, repository.ErrCellFull):
+		return nil, ErrCellFull
+	case errors.Is(err, repository.ErrPvzFull):
+		return nil, ErrPvzFull
+	case errors.Is(err, repository.ErrPvzTypeFull):
+		return nil, ErrPvzTypeFull
+	case err != nil:
+		return nil, err
+	}
+
+	if created == nil {
+		return nil, ErrNoOpenReception
+	}
+
+	return created, nil
+}
+
+func (s *receptionService) DeleteLastProduct(userID, pvzID string) (*model.Product, error) {
+	if err := s.authorize(userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	reception, err := s.db.FindOpenReception(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reception == nil {
+		return nil, ErrNoOpenReception
+	}
+
+	product, err := s.db.DeleteLastProduct(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, ErrNoProducts
+	}
+
+	return product, nil
+}
+
+func (s *receptionService) CloseLastReception(userID, pvzID string) (*model.Reception, error) {
+	if err := s.authorize(userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	reception, err := s.db.CloseReception(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reception == nil {
+		return nil, ErrNoOpenReception
+	}
+
+	return reception, nil
+}
+
+// ReopenLastReception снова открывает последнюю приёмку ПВЗ, если она закрыта не раньше reopenWindow назад.
+// Причина обязательна и попадает в журнал приёмки
+func (s *receptionService) ReopenLastReception(actorID, pvzID, reason string) (*model.Reception, error) {
+	if s.cfg.ReopenWindow <= 0 {
+		return nil, ErrReopenDisabled
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return nil, ErrReasonTooLong
+	}
+
+	pvz, err := s.db.FindPvz(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pvz == nil {
+		return nil, ErrPvzNotFound
+	}
+
+	if pvz.Status == model.PvzClosed {
+		return nil, ErrPvzClosed
+	}
+
+	last, err := s.db.FindLastReception(pvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if last == nil {
+		return nil, ErrNoClosedReception
+	}
+
+	if last.Status == model.ReceptionInProgress {
+		return nil, ErrReceptionInProgress
+	}
+
+	closedAfter := time.Now().Add(-s.cfg.ReopenWindow)
+	if last.ClosedAt == nil || last.ClosedAt.Before(closedAfter) {
+		return nil, ErrReopenExpired
+	}
+
+	// Товары, которые уже готовят к выдаче, выдали или вернули, нельзя снова править через приёмку
+	products, err := s.db.ListProductsByReceptions([]string{last.ID}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		if product.Status != model.ProductReceived {
+			return nil, ErrReceptionProcessed
+		}
+	}
+
+	reception, err := s.db.ReopenReception(last.ID, closedAfter, actorID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Между проверками и обновлением на ПВЗ открыли новую приёмку, закрыли ПВЗ или сменили статус товара
+	if reception == nil {
+		return nil, ErrReopenConflict
+	}
+
+	return reception, nil
+}
+
+func (s *receptionService) Events(receptionID string) ([]model.ReceptionEvent, error) {
+	return s.db.ListReceptionEvents(receptionID)
+}
+
+func (s *receptionService) authorize(userID, pvzID string) error {
+	return authorizeEmployee(s.db, userID, pvzID)
+}
+
+// authorizeEmployee проверяет, что ПВЗ существует и сотрудник на него назначен.
+// У токенов из /dummyLogin нет пользователя и назначений, их пропускаем без проверки:
+// такие токены принимаются только при включённом auth.dummy_login
+func authorizeEmployee(db repository.Database, userID, pvzID string) error {
+	exists, err := db.PvzExists(pvzID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrPvzNotFound
+	}
+
+	if userID == "" {
+		return nil
+	}
+
+	assigned, err := db.IsAssigned(userID, pvzID)
+	if err != nil {
+		return err
+	}
+
+	if !assigned {
+		return ErrNotAssigned
+	}
+
+	return nil
+}
+
+// checkHours пропускает открытие вне графика, если у роли есть право reception.override_hours.
+// Право читается через PermissionService, как в middleware, и берётся из его кеша
+func (s *receptionService) checkHours(role model.UserRole, pvzID string) error {
+	pvz, err := s.db.FindPvz(pvzID)
+	if err != nil {
+		return err
+	}
+
+	if pvz == nil {
+		return ErrPvzNotFound
+	}
+
+	location, err := time.LoadLocation(pvz.Timezone)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(location)
+	since := now.AddDate(0, 0, -1)
+
+	schedules, err := s.db.ListPvzSchedules([]string{pvzID}, &since)
+	if err != nil {
+		return err
+	}
+
+	if schedules[0].OpenAt(now) {
+		return nil
+	}
+
+	allowed, err := s.permissions.HasPermission(role, model.PermOverrideHours)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return ErrOutsideHours
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizeEmployee_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name     string
+		userID   string
+		db       *fakeDB
+		expected error
+	}{
+		{name: "pvz_not_found", userID: "user", db: &fakeDB{}, expected: ErrPvzNotFound},
+		{name: "not_assigned", userID: "user", db: &fakeDB{pvzExists: true}, expected: ErrNotAssigned},
+		{name: "assigned", userID: "user", db: &fakeDB{pvzExists: true, assigned: true}},
+		{name: "dummy_token", userID: "", db: &fakeDB{pvzExists: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, authorizeEmployee(tc.db, tc.userID, "pvz"), tc.expected)
+		})
+	}
+}
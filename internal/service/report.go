@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+var ErrInvalidDateRange = errors.New("date range start is after its end")
+
+type ReportService interface {
+	DailyReceptions(filter model.ReportFilter, page, limit int) ([]model.DailyReceptionStats, error)
+}
+
+type reportService struct {
+	db repository.Database
+}
+
+func NewReportService(db repository.Database) *reportService {
+	return &reportService{db}
+}
+
+func (s *reportService) DailyReceptions(filter model.ReportFilter, page, limit int) ([]model.DailyReceptionStats, error) {
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	return s.db.DailyReceptionStats(filter, limit, (page-1)*limit)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const maxReturnCommentLength = 500
+
+var (
+	ErrReturnBatchInProgress = errors.New("pvz already has an open return batch")
+	ErrNoOpenReturnBatch     = errors.New("pvz has no open return batch")
+	ErrReturnBatchEmpty      = errors.New("return batch has no returns")
+	ErrUnknownReturnReason   = errors.New("unknown return reason")
+	ErrReturnCommentTooLong  = errors.New("return comment is too long")
+)
+
+// ReturnService ведёт возвраты: партия открывается на ПВЗ, в неё попадают
+// возвращённые товары, затем партия закрывается и уезжает отправителю
+type ReturnService interface {
+	OpenBatch(userID, pvzID string) (*model.ReturnBatch, error)
+	CloseLastBatch(userID, pvzID string) (*model.ReturnBatch, error)
+	Return(userID, productID string, reason model.ReturnReason, comment string) (*model.ProductReturn, error)
+}
+
+type returnService struct {
+	db repository.Database
+}
+
+func NewReturnService(db repository.Database) *returnService {
+	return &returnService{db}
+}
+
+func (s *returnService) OpenBatch(userID, pvzID string) (*model.ReturnBatch, error) {
+	if err := authorizeEmployee(s.db, userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.db.CreateReturnBatch(pvzID, userID)
+	if errors.Is(err, repository.ErrPvzNotActive) {
+		return nil, ErrPvzNotActive
+	} else if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, ErrReturnBatchInProgress
+	}
+
+	return batch, nil
+}
+
+func (s *returnService) CloseLastBatch(userID, pvzID string) (*model.ReturnBatch, error) {
+	if err := authorizeEmployee(s.db, userID, pvzID); err != nil {
+		return nil, err
+	}
+
+	batch, err := s.db.CloseReturnBatch(pvzID)
+	if errors.Is(err, repository.ErrReturnBatchEmpty) {
+		return nil, ErrReturnBatchEmpty
+	} else if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, ErrNoOpenReturnBatch
+	}
+
+	return batch, nil
+}
+
+// Return оформляет отказ или возврат товара и кладёт его в открытую партию ПВЗ товара.
+// Вернуть можно принятый, готовый к выдаче или уже выданный товар
+func (s *returnService) Return(userID, productID string, reason model.ReturnReason, comment string) (*model.ProductReturn, error) {
+	if !slices.Contains(model.ReturnReasons, reason) {
+		return nil, ErrUnknownReturnReason
+	}
+
+	if utf8.RuneCountInString(comment) > maxReturnCommentLength {
+		return nil, ErrReturnCommentTooLong
+	}
+
+	entry, err := issuable(s.db, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status == model.ProductReturned {
+		return nil, ErrProductStatusTransition
+	}
+
+	batch, err := s.db.FindOpenReturnBatch(entry.PvzID)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, ErrNoOpenReturnBatch
+	}
+
+	productReturn, err := s.db.ReturnProduct(productID, entry.PvzID, &model.ProductReturn{
+		Reason:    reason,
+		Comment:   comment,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if productReturn == nil {
+		return nil, ErrProductStatusTransition
+	}
+
+	return productReturn, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Refresh выдаёт новый токен для той же сессии и продлевает её.
+// Обновить можно только токен пользователя с действующей сессией, токены /dummyLogin не обновляются
+func (uS *userService) Refresh(token string, ip string, userAgent string) (string, error) {
+	attempt := &model.LoginAttempt{Kind: model.LoginRefresh, IP: ip, UserAgent: userAgent}
+
+	newToken, err := uS.refresh(attempt, token)
+	uS.recordLoginAttempt(attempt, err)
+
+	return newToken, err
+}
+
+func (uS *userService) refresh(attempt *model.LoginAttempt, token string) (string, error) {
+	claims, err := uS.ParseToken(token)
+	if err != nil || claims.UserID == "" || claims.SessionID == "" {
+		attempt.Reason = model.LoginReasonInvalidToken
+		return "", ErrInvalidToken
+	}
+
+	attempt.UserID, attempt.Role = claims.UserID, claims.Role
+
+	expiresAt := time.Now().Add(tokenTTL)
+
+	extended, err := uS.db.ExtendSession(claims.SessionID, claims.UserID, attempt.IP, attempt.UserAgent, expiresAt)
+	if err != nil {
+		return "", err
+	}
+
+	if !extended {
+		attempt.Reason = model.LoginReasonSessionExpired
+		return "", ErrInvalidToken
+	}
+
+	return uS.createToken(claims.UserID, claims.SessionID, claims.Role, expiresAt)
+}
+
+func (uS *userService) ListSessions(userID string) ([]model.Session, error) {
+	return uS.db.ListActiveSessions(userID)
+}
+
+// recordLoginAttempt пишет попытку в журнал. Сбой записи не должен ломать вход,
+// поэтому ошибка игнорируется
+func (uS *userService) recordLoginAttempt(attempt *model.LoginAttempt, err error) {
+	attempt.Success = err == nil
+	if err != nil && attempt.Reason == "" {
+		attempt.Reason = model.LoginReasonInternal
+	}
+
+	_ = uS.db.CreateLoginAttempt(attempt)
+}
+
+type LoginAuditService interface {
+	History(filter model.LoginAttemptFilter, page, limit int) ([]model.LoginAttempt, error)
+}
+
+type loginAuditService struct {
+	db repository.Database
+}
+
+func NewLoginAuditService(db repository.Database) *loginAuditService {
+	return &loginAuditService{db}
+}
+
+func (s *loginAuditService) History(filter model.LoginAttemptFilter, page, limit int) ([]model.LoginAttempt, error) {
+	filter.Email = model.NormalizeEmail(filter.Email)
+
+	return s.db.ListLoginAttempts(filter, limit, (page-1)*limit)
+}
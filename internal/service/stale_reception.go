@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+// defaultStaleInterval - как часто искать зависшие приёмки, если интервал не задан
+const defaultStaleInterval = 5 * time.Minute
+
+// StaleReceptionService закрывает или помечает приёмки, которые открыты дольше TTL.
+// Забытая приёмка иначе навсегда блокирует ПВЗ индексом unique_active_reception
+type StaleReceptionService interface {
+	Interval() time.Duration
+	Action() model.ReceptionAction
+	// ProcessStale возвращает обработанные приёмки и false, если задачу сейчас выполняет другая реплика.
+	// Пустые приёмки только помечаются, даже если Action - закрытие
+	ProcessStale() ([]model.Reception, bool, error)
+}
+
+type staleReceptionService struct {
+	db       repository.Database
+	ttl      time.Duration
+	interval time.Duration
+	action   model.ReceptionAction
+}
+
+func NewStaleReceptionService(db repository.Database, cfg config.StaleReceptions) (*staleReceptionService, error) {
+	if cfg.TTL <= 0 {
+		return nil, fmt.Errorf("stale receptions ttl must be positive, got %s", cfg.TTL)
+	}
+
+	var action model.ReceptionAction
+
+	switch cfg.Action {
+	case "", "close":
+		action = model.ReceptionAutoClosed
+	case "flag":
+		action = model.ReceptionFlagged
+	default:
+		return nil, fmt.Errorf("unknown stale receptions action %q, want close or flag", cfg.Action)
+	}
+
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("stale receptions interval must not be negative, got %s", cfg.Interval)
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultStaleInterval
+	}
+
+	return &staleReceptionService{db: db, ttl: cfg.TTL, interval: interval, action: action}, nil
+}
+
+func (s *staleReceptionService) Interval() time.Duration {
+	return s.interval
+}
+
+func (s *staleReceptionService) Action() model.ReceptionAction {
+	return s.action
+}
+
+func (s *staleReceptionService) ProcessStale() ([]model.Reception, bool, error) {
+	reason := fmt.Sprintf("reception was in progress longer than %s", s.ttl)
+
+	return s.db.ProcessStaleReceptions(time.Now().Add(-s.ttl), s.action, reason)
+}
+
+// StaleAction возвращает действие, которое задача применила к приёмке из ProcessStale
+func StaleAction(reception model.Reception) model.ReceptionAction {
+	if reception.Status == model.ReceptionClose {
+		return model.ReceptionAutoClosed
+	}
+
+	return model.ReceptionFlagged
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStaleReceptionService_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name             string
+		cfg              config.StaleReceptions
+		expectErr        bool
+		expectedAction   model.ReceptionAction
+		expectedInterval time.Duration
+	}{
+		{
+			name:             "close_by_default",
+			cfg:              config.StaleReceptions{TTL: time.Hour},
+			expectedAction:   model.ReceptionAutoClosed,
+			expectedInterval: defaultStaleInterval,
+		},
+		{
+			name:             "close",
+			cfg:              config.StaleReceptions{TTL: time.Hour, Interval: time.Minute, Action: "close"},
+			expectedAction:   model.ReceptionAutoClosed,
+			expectedInterval: time.Minute,
+		},
+		{
+			name:             "flag",
+			cfg:              config.StaleReceptions{TTL: time.Hour, Interval: time.Minute, Action: "flag"},
+			expectedAction:   model.ReceptionFlagged,
+			expectedInterval: time.Minute,
+		},
+		{name: "unknown_action", cfg: config.StaleReceptions{TTL: time.Hour, Action: "delete"}, expectErr: true},
+		{name: "zero_ttl", cfg: config.StaleReceptions{Action: "close"}, expectErr: true},
+		{name: "negative_ttl", cfg: config.StaleReceptions{TTL: -time.Hour}, expectErr: true},
+		{name: "negative_interval", cfg: config.StaleReceptions{TTL: time.Hour, Interval: -time.Minute}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stale, err := NewStaleReceptionService(&fakeDB{}, tc.cfg)
+			if tc.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, stale)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAction, stale.Action())
+			assert.Equal(t, tc.expectedInterval, stale.Interval())
+		})
+	}
+}
+
+func TestProcessStale(t *testing.T) {
+	db := &staleDB{}
+
+	stale, err := NewStaleReceptionService(db, config.StaleReceptions{TTL: 2 * time.Hour, Action: "flag"})
+	assert.NoError(t, err)
+
+	_, locked, err := stale.ProcessStale()
+	assert.NoError(t, err)
+	assert.True(t, locked)
+
+	assert.Equal(t, model.ReceptionFlagged, db.action)
+	assert.WithinDuration(t, time.Now().Add(-2*time.Hour), db.before, time.Minute)
+	assert.Contains(t, db.reason, "2h0m0s")
+}
+
+func TestStaleAction_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   model.ReceptionStatus
+		expected model.ReceptionAction
+	}{
+		{name: "closed", status: model.ReceptionClose, expected: model.ReceptionAutoClosed},
+		// Пустая приёмка остаётся открытой и только помечается
+		{name: "left_open", status: model.ReceptionInProgress, expected: model.ReceptionFlagged},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, StaleAction(model.Reception{Status: tc.status}))
+		})
+	}
+}
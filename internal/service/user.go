@@ -4,32 +4,81 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/et0/avito-tech-internship-spring-2025/internal/config"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/notifier"
 	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService interface {
-	CreateToken(role model.UserRole) (string, error)
-	Register(email string, password string, role model.UserRole) (*model.User, error)
-	Login(email string, password string) (string, error)
+	CreateToken(role model.UserRole, ip string, userAgent string) (string, error)
+	Register(email string, password string, role model.UserRole, invitationCode string) (*model.User, error)
+	Login(email string, password string, ip string, userAgent string) (string, error)
+	Refresh(token string, ip string, userAgent string) (string, error)
+	ParseToken(token string) (*model.TokenClaims, error)
+	ListSessions(userID string) ([]model.Session, error)
+	Unlock(email string, ip string) error
+	ChangePassword(userID string, oldPassword string, newPassword string) error
+	RequestPasswordReset(email string) error
+	ResetPassword(token string, newPassword string) error
 }
 
+const tokenTTL = 24 * time.Hour
+
 type userService struct {
 	db        repository.Database
 	jwtSecret []byte
+	lockout   config.Lockout
+	policy    *PasswordPolicy
+	hasher    *PasswordHasher
+	notifier  notifier.Notifier
+	resetTTL  time.Duration
+	// dummyLogin - принимать токены без пользователя из /dummyLogin
+	dummyLogin bool
+}
+
+func NewUserService(db repository.Database, jwtSecret []byte, auth config.Auth, policy *PasswordPolicy, hasher *PasswordHasher, notifier notifier.Notifier) *userService {
+	return &userService{
+		db:        db,
+		jwtSecret: jwtSecret,
+		lockout:   auth.Lockout,
+		policy:    policy,
+		hasher:    hasher,
+		notifier:  notifier,
+		resetTTL:  auth.Reset.TokenTTL,
+
+		dummyLogin: auth.DummyLogin,
+	}
 }
 
-func NewUserService(db repository.Database, jwtSecret []byte) *userService {
-	return &userService{db, jwtSecret}
+// CreateToken выдаёт токен без привязки к пользователю, используется в /dummyLogin
+func (uS *userService) CreateToken(role model.UserRole, ip string, userAgent string) (string, error) {
+	attempt := &model.LoginAttempt{Kind: model.LoginDummy, Role: role, IP: ip, UserAgent: userAgent}
+
+	token, err := uS.createToken("", "", role, time.Now().Add(tokenTTL))
+	uS.recordLoginAttempt(attempt, err)
+
+	return token, err
 }
 
-func (uS *userService) CreateToken(role model.UserRole) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// createToken подписывает токен. У токенов пользователя есть user_id и sid - id сессии,
+// по которому токен можно обновить
+func (uS *userService) createToken(userID string, sessionID string, role model.UserRole, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
 		"role": role,
-		"exp":  time.Now().Add(time.Hour * 24).Unix(),
-	})
+		"exp":  expiresAt.Unix(),
+	}
+
+	if userID != "" {
+		claims["user_id"] = userID
+	}
+
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(uS.jwtSecret)
 	if err != nil {
@@ -39,7 +88,74 @@ func (uS *userService) CreateToken(role model.UserRole) (string, error) {
 	return tokenString, nil
 }
 
-func (uS *userService) Register(email string, password string, role model.UserRole) (*model.User, error) {
+func (uS *userService) ParseToken(tokenString string) (*model.TokenClaims, error) {
+	claims := jwt.MapClaims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
+		return uS.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	role, _ := claims["role"].(string)
+	if role == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		if !uS.dummyLogin {
+			return nil, fmt.Errorf("invalid token")
+		}
+
+		return &model.TokenClaims{Role: model.UserRole(role)}, nil
+	}
+
+	// Токен пользователя действует, только пока учётная запись активна,
+	// а роль берётся актуальная, чтобы её смена применялась сразу
+	user, err := uS.db.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.Disabled {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	// Сессия завершается при смене или сбросе пароля, вместе с ней перестаёт действовать токен
+	sessionID, _ := claims["sid"].(string)
+	if sessionID != "" {
+		active, err := uS.db.SessionActive(sessionID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !active {
+			return nil, fmt.Errorf("invalid token")
+		}
+	}
+
+	return &model.TokenClaims{UserID: user.ID, SessionID: sessionID, Role: user.Role}, nil
+}
+
+// Register создаёт пользователя. Для привилегированных ролей нужен код приглашения,
+// выписанный модератором на эту почту и роль
+func (uS *userService) Register(email string, password string, role model.UserRole, invitationCode string) (*model.User, error) {
+	email = model.NormalizeEmail(email)
+
+	if err := roleExists(uS.db, role); err != nil {
+		return nil, err
+	}
+
+	if role.Privileged() && invitationCode == "" {
+		return nil, ErrInvitationRequired
+	}
+
+	if err := uS.validatePassword(password); err != nil {
+		return nil, err
+	}
+
 	existingUser, err := uS.db.FindByEmail(email)
 	if err != nil {
 		return nil, err
@@ -49,32 +165,126 @@ func (uS *userService) Register(email string, password string, role model.UserRo
 		return nil, nil
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := uS.hasher.Hash(password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token")
+		return nil, fmt.Errorf("failed to hash password")
+	}
+
+	if invitationCode == "" {
+		return uS.db.CreateUser(email, hashedPassword, role)
 	}
 
-	user, err := uS.db.CreateUser(email, string(hashedPassword), role)
+	user, err := uS.db.CreateUserWithInvitation(email, hashedPassword, role, hashSecret(invitationCode))
 	if err != nil {
 		return nil, err
 	}
 
+	if user == nil {
+		return nil, ErrInvalidInvitation
+	}
+
 	return user, nil
 }
 
-func (uS *userService) Login(email string, password string) (string, error) {
+// Bootstrap создаёт первого модератора в обход приглашений. Вызывается только из CLI
+func (uS *userService) Bootstrap(email string, password string) (*model.User, error) {
+	email = model.NormalizeEmail(email)
+
+	count, err := uS.db.CountUsersByRole(model.RoleModerator)
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, ErrModeratorExists
+	}
+
+	if err := uS.validatePassword(password); err != nil {
+		return nil, err
+	}
+
+	existingUser, err := uS.db.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingUser != nil {
+		return nil, fmt.Errorf("user with this email already exists")
+	}
+
+	hashedPassword, err := uS.hasher.Hash(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password")
+	}
+
+	return uS.db.CreateUser(email, hashedPassword, model.RoleModerator)
+}
+
+func (uS *userService) Login(email string, password string, ip string, userAgent string) (string, error) {
+	attempt := &model.LoginAttempt{Kind: model.LoginPassword, Email: model.NormalizeEmail(email), IP: ip, UserAgent: userAgent}
+
+	token, err := uS.login(attempt, password)
+	uS.recordLoginAttempt(attempt, err)
+
+	return token, err
+}
+
+// login проверяет пароль и открывает сессию, по ходу заполняя attempt для журнала входов
+func (uS *userService) login(attempt *model.LoginAttempt, password string) (string, error) {
+	email, ip := attempt.Email, attempt.IP
+
+	if err := uS.checkLoginThrottle(email, ip); err != nil {
+		attempt.Reason = model.LoginReasonLocked
+		return "", err
+	}
+
 	user, err := uS.db.FindByEmail(email)
 	if err != nil {
 		return "", err
 	}
 
 	if user == nil {
+		uS.hasher.VerifyDummy(password)
+
+		attempt.Reason = model.LoginReasonUnknownUser
+		if err := uS.registerLoginFailure(email, ip); err != nil {
+			return "", err
+		}
 		return "", fmt.Errorf("User not found")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+	attempt.UserID, attempt.Role = user.ID, user.Role
+
+	if !uS.hasher.Verify(user.Password, password) {
+		attempt.Reason = model.LoginReasonInvalidPassword
+		if err := uS.registerLoginFailure(email, ip); err != nil {
+			return "", err
+		}
 		return "", fmt.Errorf("Invalid credentials")
 	}
 
-	return uS.CreateToken(user.Role)
+	if user.Disabled {
+		attempt.Reason = model.LoginReasonDisabled
+		return "", ErrUserDisabled
+	}
+
+	// Успешный вход сбрасывает счётчик по почте; счётчик по IP истекает сам через reset_after
+	if err := uS.db.DeleteLoginThrottle(model.ThrottleEmail, email); err != nil {
+		return "", err
+	}
+
+	// Пароль перехешируется, если поменялся алгоритм или его параметры.
+	// Ошибка обновления не должна мешать входу: попробуем в следующий раз
+	if uS.hasher.NeedsRehash(user.Password) {
+		if hashedPassword, err := uS.hasher.Hash(password); err == nil {
+			_ = uS.db.UpdatePassword(user.ID, hashedPassword)
+		}
+	}
+
+	session, err := uS.db.CreateSession(user.ID, ip, attempt.UserAgent, time.Now().Add(tokenTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return uS.createToken(user.ID, session.ID, user.Role, session.ExpiresAt)
 }
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/et0/avito-tech-internship-spring-2025/internal/repository"
+)
+
+const (
+	DefaultPageLimit = 10
+	MaxPageLimit     = 30
+)
+
+var ErrSelfModification = errors.New("moderator cannot modify own account")
+
+type UserAdminService interface {
+	List(email string, page, limit int) ([]model.User, error)
+	Get(id string) (*model.User, error)
+	ChangeRole(actorID, id string, role model.UserRole) (*model.User, error)
+	SetDisabled(actorID, id string, disabled bool) (*model.User, error)
+	Delete(actorID, id string) error
+}
+
+type userAdminService struct {
+	db repository.Database
+}
+
+func NewUserAdminService(db repository.Database) *userAdminService {
+	return &userAdminService{db}
+}
+
+func (s *userAdminService) List(email string, page, limit int) ([]model.User, error) {
+	return s.db.ListUsers(email, limit, (page-1)*limit)
+}
+
+func (s *userAdminService) Get(id string) (*model.User, error) {
+	user, err := s.db.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s *userAdminService) ChangeRole(actorID, id string, role model.UserRole) (*model.User, error) {
+	if actorID == id {
+		return nil, ErrSelfModification
+	}
+
+	if err := roleExists(s.db, role); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.UpdateUserRole(id, role); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+// SetDisabled блокирует или разблокирует учётную запись.
+// Токены заблокированного пользователя перестают приниматься сразу, см. userService.ParseToken
+func (s *userAdminService) SetDisabled(actorID, id string, disabled bool) (*model.User, error) {
+	if actorID == id {
+		return nil, ErrSelfModification
+	}
+
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.SetUserDisabled(id, disabled); err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+func (s *userAdminService) Delete(actorID, id string) error {
+	if actorID == id {
+		return ErrSelfModification
+	}
+
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	return s.db.SoftDeleteUser(id)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/et0/avito-tech-internship-spring-2025/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDummyToken_TableDriven(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dummyLogin bool
+		expectErr  bool
+	}{
+		{name: "dummy_login_enabled", dummyLogin: true},
+		{name: "dummy_login_disabled", dummyLogin: false, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uS := &userService{jwtSecret: []byte("secret"), dummyLogin: tc.dummyLogin}
+
+			token, err := uS.createToken("", "", model.RoleEmployee, time.Now().Add(time.Hour))
+			assert.NoError(t, err)
+
+			claims, err := uS.ParseToken(token)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &model.TokenClaims{Role: model.RoleEmployee}, claims)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+-- Удалённые пользователи не стираются ради уникальности почты: если почту удалённого
+-- уже занял новый пользователь, откат останавливается, и разбирать такие записи нужно вручную
+DO $$
+DECLARE
+    duplicate TEXT;
+BEGIN
+    SELECT email INTO duplicate FROM users GROUP BY email HAVING COUNT(*) > 1 LIMIT 1;
+
+    IF duplicate IS NOT NULL THEN
+        RAISE EXCEPTION 'cannot restore unique users.email: % is shared by more than one account', duplicate
+            USING HINT = 'Rename or remove the deleted accounts sharing an email before rolling back';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled;
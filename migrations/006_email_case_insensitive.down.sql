@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS users_email_lower_active;
+CREATE UNIQUE INDEX users_email_active ON users (email) WHERE deleted_at IS NULL;
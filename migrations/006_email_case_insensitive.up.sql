@@ -0,0 +1,8 @@
+-- Перед применением проверьте дубликаты: make check-email-duplicates.
+-- При наличии дубликатов создание индекса упадёт и миграция откатится
+DROP INDEX IF EXISTS users_email_active;
+
+UPDATE users SET email = lower(btrim(email)) WHERE email <> lower(btrim(email));
+UPDATE invitations SET email = lower(btrim(email)) WHERE email <> lower(btrim(email));
+
+CREATE UNIQUE INDEX users_email_lower_active ON users (lower(email)) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS pvz_assignments (
+    user_id UUID NOT NULL REFERENCES users(id),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, pvz_id)
+);
+
+CREATE INDEX pvz_assignments_pvz_id ON pvz_assignments (pvz_id);
@@ -0,0 +1,10 @@
+-- Откат упадёт, если есть пользователи или приглашения с ролями кроме employee и moderator
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_role_fkey;
+ALTER TABLE invitations ADD CONSTRAINT invitations_role_check CHECK (role IN ('employee', 'moderator'));
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('employee', 'moderator'));
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    builtin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions(name),
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, builtin) VALUES
+    ('employee', 'Сотрудник ПВЗ', TRUE),
+    ('moderator', 'Модератор', TRUE),
+    ('auditor', 'Аудитор, только чтение', FALSE),
+    ('supervisor', 'Старший смены, может переоткрывать приёмки', FALSE);
+
+INSERT INTO permissions (name, description) VALUES
+    ('pvz.create', 'Заведение ПВЗ'),
+    ('pvz.read', 'Просмотр ПВЗ, приёмок и товаров'),
+    ('reception.open', 'Открытие приёмки'),
+    ('reception.close', 'Закрытие приёмки'),
+    ('reception.reopen', 'Переоткрытие закрытой приёмки'),
+    ('product.add', 'Добавление товара в приёмку'),
+    ('product.delete', 'Удаление товара из приёмки'),
+    ('report.view', 'Просмотр отчётов'),
+    ('user.manage', 'Управление пользователями'),
+    ('invitation.create', 'Создание приглашений'),
+    ('assignment.manage', 'Назначение сотрудников на ПВЗ'),
+    ('apikey.manage', 'Управление API-ключами'),
+    ('role.manage', 'Управление ролями и правами');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('employee', 'pvz.read'),
+    ('employee', 'reception.open'),
+    ('employee', 'reception.close'),
+    ('employee', 'product.add'),
+    ('employee', 'product.delete'),
+    ('moderator', 'pvz.create'),
+    ('moderator', 'pvz.read'),
+    ('moderator', 'report.view'),
+    ('moderator', 'user.manage'),
+    ('moderator', 'invitation.create'),
+    ('moderator', 'assignment.manage'),
+    ('moderator', 'apikey.manage'),
+    ('moderator', 'role.manage'),
+    ('auditor', 'pvz.read'),
+    ('auditor', 'report.view'),
+    ('supervisor', 'pvz.read'),
+    ('supervisor', 'report.view'),
+    ('supervisor', 'reception.reopen');
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
+
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_role_check;
+ALTER TABLE invitations ADD CONSTRAINT invitations_role_fkey FOREIGN KEY (role) REFERENCES roles(name);
@@ -0,0 +1,5 @@
+DELETE FROM role_permissions WHERE permission = 'audit.view';
+DELETE FROM permissions WHERE name = 'audit.view';
+
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission = 'city.manage';
+DELETE FROM permissions WHERE name = 'city.manage';
+
+-- Откат упадёт, если есть ПВЗ в городах кроме исходных трёх
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_fkey;
+ALTER TABLE pvz ADD CONSTRAINT pvz_city_check CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'));
+
+DROP TABLE IF EXISTS city_renames;
+DROP TABLE IF EXISTS cities;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS cities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cities_name_lower ON cities(lower(name));
+
+CREATE TABLE IF NOT EXISTS city_renames (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    city_id UUID NOT NULL REFERENCES cities(id) ON DELETE CASCADE,
+    old_name TEXT NOT NULL,
+    new_name TEXT NOT NULL,
+    changed_by UUID REFERENCES users(id),
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS city_renames_city_id ON city_renames(city_id, changed_at);
+
+INSERT INTO cities (name) VALUES ('Москва'), ('Санкт-Петербург'), ('Казань');
+
+-- Переименование города каскадом обновляет pvz.city
+ALTER TABLE pvz DROP CONSTRAINT IF EXISTS pvz_city_check;
+ALTER TABLE pvz ADD CONSTRAINT pvz_city_fkey FOREIGN KEY (city) REFERENCES cities(name) ON UPDATE CASCADE;
+
+INSERT INTO permissions (name, description) VALUES
+    ('city.manage', 'Управление справочником городов');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'city.manage');
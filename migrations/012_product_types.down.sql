@@ -0,0 +1,8 @@
+DELETE FROM role_permissions WHERE permission = 'product_type.manage';
+DELETE FROM permissions WHERE name = 'product_type.manage';
+
+-- Откат упадёт, если есть товары с типами кроме исходных трёх
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_fkey;
+ALTER TABLE products ADD CONSTRAINT products_type_check CHECK (type IN ('электроника', 'одежда', 'обувь'));
+
+DROP TABLE IF EXISTS product_types;
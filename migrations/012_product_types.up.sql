@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS product_types (
+    code TEXT PRIMARY KEY,
+    names JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO product_types (code, names) VALUES
+    ('электроника', '{"ru": "Электроника", "en": "Electronics"}'),
+    ('одежда', '{"ru": "Одежда", "en": "Clothes"}'),
+    ('обувь', '{"ru": "Обувь", "en": "Shoes"}');
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_type_check;
+ALTER TABLE products ADD CONSTRAINT products_type_fkey FOREIGN KEY (type) REFERENCES product_types(code);
+
+INSERT INTO permissions (name, description) VALUES
+    ('product_type.manage', 'Управление справочником типов товаров');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'product_type.manage');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS products_reception_id;
+DROP INDEX IF EXISTS receptions_pvz_id_created_at;
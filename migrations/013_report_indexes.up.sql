@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS receptions_pvz_id_created_at ON receptions(pvz_id, created_at);
+CREATE INDEX IF NOT EXISTS products_reception_id ON products(reception_id);
@@ -0,0 +1,57 @@
+ALTER TABLE pvz DROP COLUMN IF EXISTS timezone;
+
+ALTER TABLE users
+    ALTER COLUMN created_at TYPE TIMESTAMP,
+    ALTER COLUMN deleted_at TYPE TIMESTAMP;
+
+ALTER TABLE pvz
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE receptions
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE products
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE login_throttles
+    ALTER COLUMN last_failure_at TYPE TIMESTAMP,
+    ALTER COLUMN locked_until TYPE TIMESTAMP;
+
+ALTER TABLE password_reset_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMP,
+    ALTER COLUMN used_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE invitations
+    ALTER COLUMN expires_at TYPE TIMESTAMP,
+    ALTER COLUMN used_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE pvz_assignments
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE api_keys
+    ALTER COLUMN expires_at TYPE TIMESTAMP,
+    ALTER COLUMN last_used_at TYPE TIMESTAMP,
+    ALTER COLUMN revoked_at TYPE TIMESTAMP,
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE roles
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE login_attempts
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE sessions
+    ALTER COLUMN created_at TYPE TIMESTAMP,
+    ALTER COLUMN refreshed_at TYPE TIMESTAMP,
+    ALTER COLUMN expires_at TYPE TIMESTAMP;
+
+ALTER TABLE cities
+    ALTER COLUMN created_at TYPE TIMESTAMP;
+
+ALTER TABLE city_renames
+    ALTER COLUMN changed_at TYPE TIMESTAMP;
+
+ALTER TABLE product_types
+    ALTER COLUMN created_at TYPE TIMESTAMP;
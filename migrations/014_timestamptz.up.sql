@@ -0,0 +1,59 @@
+-- Старые значения записаны NOW() в часовом поясе сервера, поэтому трактуются в текущем TimeZone сессии
+ALTER TABLE users
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE pvz
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE receptions
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE products
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE login_throttles
+    ALTER COLUMN last_failure_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN locked_until TYPE TIMESTAMPTZ;
+
+ALTER TABLE password_reset_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN used_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE invitations
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN used_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE pvz_assignments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE api_keys
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN last_used_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN revoked_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE roles
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE login_attempts
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE sessions
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN refreshed_at TYPE TIMESTAMPTZ,
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE cities
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE city_renames
+    ALTER COLUMN changed_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE product_types
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+-- Часовой пояс ПВЗ в формате IANA. Все исходные города живут по московскому времени
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'Europe/Moscow';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS products_barcode;
+
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
@@ -0,0 +1,4 @@
+-- Штрихкод или номер заказа. У товаров, принятых до его появления, он пустой
+ALTER TABLE products ADD COLUMN IF NOT EXISTS barcode TEXT;
+
+CREATE INDEX IF NOT EXISTS products_barcode ON products (barcode) WHERE barcode IS NOT NULL;
@@ -0,0 +1,11 @@
+DELETE FROM role_permissions WHERE permission = 'product.issue';
+DELETE FROM permissions WHERE name = 'product.issue';
+
+DROP INDEX IF EXISTS products_reception_id_status;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS issued_by,
+    DROP COLUMN IF EXISTS issue_attempts,
+    DROP COLUMN IF EXISTS issue_code_hash,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,17 @@
+-- Жизненный цикл товара на ПВЗ: received -> ready -> issued / returned
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'received'
+        CONSTRAINT products_status_check CHECK (status IN ('received', 'ready', 'issued', 'returned')),
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ,
+    -- Хеш кода получателя, выдаётся при переводе в ready
+    ADD COLUMN IF NOT EXISTS issue_code_hash TEXT,
+    ADD COLUMN IF NOT EXISTS issue_attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS issued_by UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS products_reception_id_status ON products (reception_id, status);
+
+INSERT INTO permissions (name, description) VALUES
+    ('product.issue', 'Выдача и возврат товаров');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('employee', 'product.issue');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_returns;
+DROP TABLE IF EXISTS return_batches;
@@ -0,0 +1,24 @@
+-- Партии возвратов ведутся как приёмки: на ПВЗ не больше одной открытой партии
+CREATE TABLE IF NOT EXISTS return_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    status TEXT NOT NULL CHECK (status IN ('in_progress', 'close')),
+    created_by UUID REFERENCES users(id),
+    closed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX unique_active_return_batch ON return_batches (pvz_id) WHERE (status = 'in_progress');
+CREATE INDEX IF NOT EXISTS return_batches_pvz_id_created_at ON return_batches (pvz_id, created_at);
+
+CREATE TABLE IF NOT EXISTS product_returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL UNIQUE REFERENCES products(id),
+    batch_id UUID NOT NULL REFERENCES return_batches(id),
+    reason TEXT NOT NULL CHECK (reason IN ('refused', 'not_collected', 'damaged', 'wrong_item', 'customer_return')),
+    comment TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS product_returns_batch_id ON product_returns (batch_id);
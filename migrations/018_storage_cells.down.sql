@@ -0,0 +1,9 @@
+DELETE FROM role_permissions WHERE permission IN ('cell.manage', 'product.move');
+DELETE FROM permissions WHERE name IN ('cell.manage', 'product.move');
+
+DROP TABLE IF EXISTS product_cell_moves;
+
+DROP INDEX IF EXISTS products_cell_id;
+ALTER TABLE products DROP COLUMN IF EXISTS cell_id;
+
+DROP TABLE IF EXISTS storage_cells;
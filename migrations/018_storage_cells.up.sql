@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS storage_cells (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    code TEXT NOT NULL,
+    capacity INT NOT NULL CHECK (capacity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (pvz_id, code)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cell_id UUID REFERENCES storage_cells(id);
+
+CREATE INDEX IF NOT EXISTS products_cell_id ON products (cell_id) WHERE cell_id IS NOT NULL;
+
+-- Каждое размещение товара, включая первое. from_cell_id пуст, если товар ещё не лежал в ячейке
+CREATE TABLE IF NOT EXISTS product_cell_moves (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id),
+    from_cell_id UUID REFERENCES storage_cells(id),
+    to_cell_id UUID NOT NULL REFERENCES storage_cells(id),
+    moved_by UUID REFERENCES users(id),
+    moved_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS product_cell_moves_product_id ON product_cell_moves (product_id, moved_at);
+
+INSERT INTO permissions (name, description) VALUES
+    ('cell.manage', 'Управление ячейками хранения ПВЗ'),
+    ('product.move', 'Размещение товаров по ячейкам');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'cell.manage'),
+    ('employee', 'product.move');
@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'pvz.manage';
+DELETE FROM permissions WHERE name = 'pvz.manage';
+
+DROP TABLE IF EXISTS pvz_type_capacities;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,15 @@
+-- Лимиты ПВЗ на товары, которые сейчас на ПВЗ. NULL и отсутствие строки - лимита нет
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS capacity INT CHECK (capacity > 0);
+
+CREATE TABLE IF NOT EXISTS pvz_type_capacities (
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    type TEXT NOT NULL REFERENCES product_types(code),
+    capacity INT NOT NULL CHECK (capacity > 0),
+    PRIMARY KEY (pvz_id, type)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('pvz.manage', 'Настройка параметров ПВЗ');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'pvz.manage');
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS pvz_status;
+
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS status_changed_by,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS address,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,10 @@
+-- suspended - новые приёмки не открываются, closed - ПВЗ закрыт навсегда
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'closed')),
+    ADD COLUMN IF NOT EXISTS status_reason TEXT,
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS status_changed_by UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS pvz_status ON pvz (status);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS pvz_location;
+
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '';
+
+UPDATE pvz SET address = concat_ws(', ', NULLIF(address_postal_code, ''), NULLIF(address_street, ''), NULLIF(address_house, ''));
+
+ALTER TABLE pvz
+    DROP CONSTRAINT IF EXISTS pvz_location_check,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS address_house,
+    DROP COLUMN IF EXISTS address_street,
+    DROP COLUMN IF EXISTS address_postal_code;
@@ -0,0 +1,15 @@
+-- Адрес хранится по частям. Прежний адрес одной строкой переносится в улицу
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS address_postal_code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS address_street TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS address_house TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD CONSTRAINT pvz_location_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+UPDATE pvz SET address_street = address WHERE address <> '';
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS address;
+
+-- Поиск ближайших ПВЗ сначала отсекает строки по ограничивающему прямоугольнику
+CREATE INDEX IF NOT EXISTS pvz_location ON pvz (latitude, longitude) WHERE status = 'active' AND latitude IS NOT NULL;
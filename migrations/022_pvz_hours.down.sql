@@ -0,0 +1,5 @@
+DELETE FROM role_permissions WHERE permission = 'reception.override_hours';
+DELETE FROM permissions WHERE name = 'reception.override_hours';
+
+DROP TABLE IF EXISTS pvz_hours_exceptions;
+DROP TABLE IF EXISTS pvz_opening_hours;
@@ -0,0 +1,24 @@
+-- Недельный график: одна строка на день недели, 1 - понедельник, 7 - воскресенье.
+-- ПВЗ без графика работает круглосуточно, дни без строки - выходные
+CREATE TABLE IF NOT EXISTS pvz_opening_hours (
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 1 AND 7),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL CHECK (closes_at > opens_at),
+    PRIMARY KEY (pvz_id, weekday)
+);
+
+-- Праздники и сокращённые дни заменяют недельный график на конкретную дату
+CREATE TABLE IF NOT EXISTS pvz_hours_exceptions (
+    pvz_id UUID NOT NULL REFERENCES pvz(id),
+    date DATE NOT NULL,
+    opens_at TIME,
+    closes_at TIME,
+    note TEXT,
+    PRIMARY KEY (pvz_id, date),
+    CHECK ((opens_at IS NULL AND closes_at IS NULL) OR closes_at > opens_at)
+);
+
+-- Встроенным ролям не выдаётся: заводится своя роль через /roles
+INSERT INTO permissions (name, description) VALUES
+    ('reception.override_hours', 'Открытие приёмки вне часов работы ПВЗ');
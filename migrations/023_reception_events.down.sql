@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS receptions_in_progress_created_at;
+
+DROP TABLE IF EXISTS reception_events;
+
+ALTER TABLE receptions DROP COLUMN IF EXISTS flagged_at;
@@ -0,0 +1,16 @@
+-- Приёмки, которые фоновая задача пометила для разбора, но не закрыла
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMPTZ;
+
+-- Служебные действия с приёмками и их причины. actor_id пуст у действий фоновых задач
+CREATE TABLE IF NOT EXISTS reception_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reception_id UUID NOT NULL REFERENCES receptions(id),
+    action TEXT NOT NULL CHECK (action IN ('auto_closed', 'flagged')),
+    reason TEXT NOT NULL,
+    actor_id UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reception_events_reception_id ON reception_events (reception_id, created_at);
+
+CREATE INDEX IF NOT EXISTS receptions_in_progress_created_at ON receptions (created_at) WHERE status = 'in_progress';
@@ -0,0 +1,16 @@
+DELETE FROM role_permissions WHERE role = 'moderator' AND permission = 'reception.reopen';
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('supervisor', 'reception.reopen')
+ON CONFLICT DO NOTHING;
+UPDATE roles SET description = 'Старший смены, может переоткрывать приёмки'
+WHERE name = 'supervisor' AND description = 'Старший смены, просмотр ПВЗ и отчётов';
+
+-- Записи о переоткрытиях - журнал аудита, откат их не удаляет,
+-- поэтому 'reopened' остаётся допустимым действием
+
+DROP INDEX IF EXISTS receptions_in_progress_opened_at;
+CREATE INDEX IF NOT EXISTS receptions_in_progress_created_at ON receptions (created_at) WHERE status = 'in_progress';
+
+ALTER TABLE receptions DROP COLUMN IF EXISTS reopened_at;
+ALTER TABLE receptions DROP COLUMN IF EXISTS closed_at;
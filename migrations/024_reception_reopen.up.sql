@@ -0,0 +1,24 @@
+-- Время закрытия нужно для окна переоткрытия. У приёмок, закрытых до миграции, его нет,
+-- и переоткрыть их нельзя
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+
+-- Переоткрытая приёмка считается зависшей от момента переоткрытия, а не создания
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS reopened_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS receptions_in_progress_created_at;
+CREATE INDEX IF NOT EXISTS receptions_in_progress_opened_at ON receptions ((COALESCE(reopened_at, created_at)))
+    WHERE status = 'in_progress';
+
+ALTER TABLE reception_events DROP CONSTRAINT IF EXISTS reception_events_action_check;
+ALTER TABLE reception_events ADD CONSTRAINT reception_events_action_check
+    CHECK (action IN ('auto_closed', 'flagged', 'reopened'));
+
+-- Переоткрытие разрешено только модераторам. Право из 009 у супервизора отзывается,
+-- и описание роли больше его не обещает
+DELETE FROM role_permissions WHERE role = 'supervisor' AND permission = 'reception.reopen';
+UPDATE roles SET description = 'Старший смены, просмотр ПВЗ и отчётов'
+WHERE name = 'supervisor' AND description = 'Старший смены, может переоткрывать приёмки';
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('moderator', 'reception.reopen')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,10 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_dimensions_check;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS condition,
+    DROP COLUMN IF EXISTS declared_value,
+    DROP COLUMN IF EXISTS height_mm,
+    DROP COLUMN IF EXISTS width_mm,
+    DROP COLUMN IF EXISTS length_mm,
+    DROP COLUMN IF EXISTS weight_grams;
@@ -0,0 +1,14 @@
+-- Необязательные сведения о товаре при приёмке. Вес в граммах, габариты в миллиметрах,
+-- объявленная ценность в копейках. Габариты задаются либо все три, либо ни одного
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS weight_grams INT CHECK (weight_grams > 0),
+    ADD COLUMN IF NOT EXISTS length_mm INT CHECK (length_mm > 0),
+    ADD COLUMN IF NOT EXISTS width_mm INT CHECK (width_mm > 0),
+    ADD COLUMN IF NOT EXISTS height_mm INT CHECK (height_mm > 0),
+    ADD COLUMN IF NOT EXISTS declared_value BIGINT CHECK (declared_value >= 0),
+    ADD COLUMN IF NOT EXISTS condition TEXT NOT NULL DEFAULT 'ok'
+        CHECK (condition IN ('ok', 'damaged', 'wet', 'opened')),
+    ADD COLUMN IF NOT EXISTS note TEXT;
+
+ALTER TABLE products ADD CONSTRAINT products_dimensions_check
+    CHECK ((length_mm IS NULL) = (width_mm IS NULL) AND (width_mm IS NULL) = (height_mm IS NULL));
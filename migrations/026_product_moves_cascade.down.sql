@@ -0,0 +1,3 @@
+ALTER TABLE product_cell_moves DROP CONSTRAINT IF EXISTS product_cell_moves_product_id_fkey;
+ALTER TABLE product_cell_moves ADD CONSTRAINT product_cell_moves_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id);
@@ -0,0 +1,5 @@
+-- История размещений удаляется вместе с товаром, иначе delete_last_product
+-- падает на внешнем ключе для товара, который успели положить в ячейку
+ALTER TABLE product_cell_moves DROP CONSTRAINT IF EXISTS product_cell_moves_product_id_fkey;
+ALTER TABLE product_cell_moves ADD CONSTRAINT product_cell_moves_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
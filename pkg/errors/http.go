@@ -5,3 +5,11 @@ import "net/http"
 func BadRequest(message string) *AppError {
 	return New(http.StatusBadRequest, message)
 }
+
+func Unauthorized(message string) *AppError {
+	return New(http.StatusUnauthorized, message)
+}
+
+func Forbidden(message string) *AppError {
+	return New(http.StatusForbidden, message)
+}
@@ -2,4 +2,9 @@ package errors
 
 const (
 	MessageInvalidEmail = "Email must be correct and not empty"
+	MessageUnauthorized = "Authorization token is missing or invalid"
+	MessageForbidden    = "Access denied"
+
+	MessageAPIKeyNotAllowed = "API keys are not accepted for this endpoint"
+	MessageMissingScope     = "API key does not have the required scope"
 )